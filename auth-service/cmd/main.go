@@ -1,19 +1,29 @@
 package main
 
 import (
+	grpcAdapter "auth-service/internal/adapters/grpc"
 	httpAdapter "auth-service/internal/adapters/http"
+	kafkaAdapter "auth-service/internal/adapters/kafka"
+	mailAdapter "auth-service/internal/adapters/mail"
 	mongoAdapter "auth-service/internal/adapters/mongo"
+	"auth-service/internal/domain"
 	"auth-service/internal/service"
+	"auth-service/pkg/authpb"
 	"context"
 	"log"
+	"net"
 	"net/http"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 )
 
 func main() {
@@ -22,6 +32,119 @@ func main() {
 	collection := os.Getenv("MONGO_COLLECTION")
 	jwtSecret := os.Getenv("JWT_SECRET")
 	jwtExpiry, _ := strconv.Atoi(os.Getenv("JWT_EXPIRY"))
+	if jwtExpiry == 0 {
+		jwtExpiry = 3600
+	}
+	refreshExpiry, _ := strconv.Atoi(os.Getenv("REFRESH_TOKEN_EXPIRY"))
+	if refreshExpiry == 0 {
+		refreshExpiry = 7 * 24 * 3600
+	}
+	resetExpiry, _ := strconv.Atoi(os.Getenv("PASSWORD_RESET_EXPIRY"))
+	if resetExpiry == 0 {
+		resetExpiry = 3600
+	}
+	appBaseURL := os.Getenv("APP_BASE_URL")
+	if appBaseURL == "" {
+		appBaseURL = "http://localhost:3001"
+	}
+	mailOutbox := os.Getenv("MAIL_OUTBOX_DIR")
+	if mailOutbox == "" {
+		mailOutbox = "./mail-outbox"
+	}
+	mailFrom := os.Getenv("MAIL_FROM")
+	if mailFrom == "" {
+		mailFrom = "no-reply@shop.local"
+	}
+	verificationExpiry, _ := strconv.Atoi(os.Getenv("VERIFICATION_EXPIRY"))
+	if verificationExpiry == 0 {
+		verificationExpiry = 24 * 3600
+	}
+	verificationResendInterval, _ := strconv.Atoi(os.Getenv("VERIFICATION_RESEND_INTERVAL"))
+	if verificationResendInterval == 0 {
+		verificationResendInterval = 60
+	}
+	unverifiedLogin := os.Getenv("UNVERIFIED_LOGIN")
+	if unverifiedLogin == "" {
+		unverifiedLogin = service.UnverifiedLoginDeny
+	}
+	loginMaxFailures, _ := strconv.Atoi(os.Getenv("LOGIN_MAX_FAILURES"))
+	if loginMaxFailures == 0 {
+		loginMaxFailures = 5
+	}
+	loginLockout, _ := strconv.Atoi(os.Getenv("LOGIN_LOCKOUT_DURATION"))
+	if loginLockout == 0 {
+		loginLockout = 900
+	}
+	loginBackoffMs, _ := strconv.Atoi(os.Getenv("LOGIN_BACKOFF_BASE_MS"))
+	if loginBackoffMs == 0 {
+		loginBackoffMs = 500
+	}
+	loginMaxFailuresPerIP, _ := strconv.Atoi(os.Getenv("LOGIN_MAX_FAILURES_PER_IP"))
+	if loginMaxFailuresPerIP == 0 {
+		loginMaxFailuresPerIP = 50
+	}
+	loginIPWindow, _ := strconv.Atoi(os.Getenv("LOGIN_IP_WINDOW"))
+	if loginIPWindow == 0 {
+		loginIPWindow = 900
+	}
+	mfaIssuer := os.Getenv("MFA_ISSUER")
+	if mfaIssuer == "" {
+		mfaIssuer = "Cloud-Native Shop"
+	}
+	mfaPendingExpiry, _ := strconv.Atoi(os.Getenv("MFA_PENDING_EXPIRY"))
+	if mfaPendingExpiry == 0 {
+		mfaPendingExpiry = 300
+	}
+	serviceTokenExpiry, _ := strconv.Atoi(os.Getenv("SERVICE_TOKEN_EXPIRY"))
+	if serviceTokenExpiry == 0 {
+		serviceTokenExpiry = 300
+	}
+	kafkaBroker := os.Getenv("KAFKA_BROKER")
+	if kafkaBroker == "" {
+		kafkaBroker = "kafka:9092"
+	}
+	passwordHashAlg := os.Getenv("PASSWORD_HASH_ALG")
+	if passwordHashAlg == "" {
+		passwordHashAlg = service.HashArgon2id
+	}
+	// Standardwerte nach OWASP-Empfehlung für argon2id
+	argon2Memory, _ := strconv.Atoi(os.Getenv("ARGON2_MEMORY_KB"))
+	if argon2Memory == 0 {
+		argon2Memory = 19456
+	}
+	argon2Iterations, _ := strconv.Atoi(os.Getenv("ARGON2_ITERATIONS"))
+	if argon2Iterations == 0 {
+		argon2Iterations = 2
+	}
+	argon2Parallelism, _ := strconv.Atoi(os.Getenv("ARGON2_PARALLELISM"))
+	if argon2Parallelism == 0 {
+		argon2Parallelism = 1
+	}
+	bcryptCost, _ := strconv.Atoi(os.Getenv("BCRYPT_COST"))
+	if bcryptCost == 0 {
+		bcryptCost = 12
+	}
+	oidcCodeExpiry, _ := strconv.Atoi(os.Getenv("OIDC_CODE_EXPIRY"))
+	if oidcCodeExpiry == 0 {
+		oidcCodeExpiry = 60
+	}
+	passwordMinLength, _ := strconv.Atoi(os.Getenv("PASSWORD_MIN_LENGTH"))
+	if passwordMinLength == 0 {
+		passwordMinLength = 8
+	}
+	// Services, die bei Export- und Löschanfragen antworten müssen
+	dataRequestServices := os.Getenv("DATA_REQUEST_SERVICES")
+	if dataRequestServices == "" {
+		dataRequestServices = "shopping-service,payment-service"
+	}
+	dataRequestRetention, _ := strconv.Atoi(os.Getenv("DATA_REQUEST_RETENTION_DAYS"))
+	if dataRequestRetention == 0 {
+		dataRequestRetention = 30
+	}
+	grpcPort, _ := strconv.Atoi(os.Getenv("GRPC_PORT"))
+	if grpcPort == 0 {
+		grpcPort = 9091
+	}
 
 	client, err := mongo.NewClient(options.Client().ApplyURI(mongoURI))
 	if err != nil {
@@ -34,15 +157,112 @@ func main() {
 	}
 
 	db := client.Database(dbName)
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		os.Exit(runMigrate(db, collection, os.Args[2:]))
+	}
+	if err := mongoAdapter.MarkLegacyUsersVerified(db, collection); err != nil {
+		log.Fatal(err)
+	}
+	migrateCtx, cancelMigrate := context.WithTimeout(context.Background(), 10*time.Minute)
+	emailReport, err := mongoAdapter.MigrateEmails(migrateCtx, db, collection)
+	cancelMigrate()
+	if err != nil {
+		log.Fatal("Email migration failed: ", err)
+	}
+	if emailReport.Normalized > 0 {
+		log.Printf("Normalized %d email addresses", emailReport.Normalized)
+	}
 	repo := mongoAdapter.NewUserRepository(db, collection)
-	authService := service.NewAuthService(repo, jwtSecret, jwtExpiry)
+	// Ohne JWT_SIGNING_ALG wird weiterhin mit dem gemeinsamen JWT_SECRET signiert.
+	var keys *service.KeySet
+	if signingAlg := os.Getenv("JWT_SIGNING_ALG"); signingAlg == "" || signingAlg == "HS256" {
+		keys, err = service.NewHMACKeySet(jwtSecret)
+	} else {
+		keys, err = service.LoadKeySet(signingAlg, os.Getenv("JWT_KEYS_DIR"), os.Getenv("JWT_ACTIVE_KID"))
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	hasher, err := service.NewPasswordHasher(service.HasherConfig{
+		Algorithm: passwordHashAlg,
+		Argon2: service.Argon2Params{
+			Memory:      uint32(argon2Memory),
+			Iterations:  uint32(argon2Iterations),
+			Parallelism: uint8(argon2Parallelism),
+		},
+		BcryptCost: bcryptCost,
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	passwordPolicy, err := service.LoadPasswordPolicy(passwordMinLength, os.Getenv("PASSWORD_BREACHED_LIST"))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	mailer, err := mailAdapter.NewFileMailer(mailOutbox, mailFrom)
+	if err != nil {
+		log.Fatal(err)
+	}
+	oneTimeTokens := mongoAdapter.NewOneTimeTokenRepository(db)
+	refreshRepo := mongoAdapter.NewRefreshTokenRepository(db)
+
+	verificationService := service.NewVerificationService(repo, oneTimeTokens, mailer, verificationExpiry, verificationResendInterval, appBaseURL)
+	// Alle Events landen zusätzlich im durchsuchbaren Audit-Log.
+	auditRepo := mongoAdapter.NewAuditRepository(db)
+	eventPublisher := service.NewAuditedPublisher(auditRepo, kafkaAdapter.NewEventPublisher(kafkaBroker))
+	authService := service.NewAuthService(repo, refreshRepo, mongoAdapter.NewRevocationRepository(db), mongoAdapter.NewSessionRepository(db), verificationService, eventPublisher, keys, hasher, passwordPolicy, service.AuthConfig{
+		AccessExpiry:    time.Duration(jwtExpiry) * time.Second,
+		RefreshExpiry:   time.Duration(refreshExpiry) * time.Second,
+		UnverifiedLogin: unverifiedLogin,
+		Lockout: service.LockoutConfig{
+			MaxFailures:      loginMaxFailures,
+			LockoutDuration:  time.Duration(loginLockout) * time.Second,
+			BackoffBase:      time.Duration(loginBackoffMs) * time.Millisecond,
+			MaxFailuresPerIP: loginMaxFailuresPerIP,
+			IPWindow:         time.Duration(loginIPWindow) * time.Second,
+		},
+		MFAIssuer:        mfaIssuer,
+		MFAPendingExpiry: time.Duration(mfaPendingExpiry) * time.Second,
+	})
+	roleService := service.NewRoleService(repo, mongoAdapter.NewRoleChangeRepository(db), hasher, eventPublisher, authService)
+	passwordService := service.NewPasswordService(repo, oneTimeTokens, authService, mailer, hasher, passwordPolicy, resetExpiry, appBaseURL)
+
+	// Erster Admin aus der Konfiguration, da Registrierung nur noch "user" vergibt
+	if adminEmail := os.Getenv("BOOTSTRAP_ADMIN_EMAIL"); adminEmail != "" {
+		if err := roleService.BootstrapAdmin(context.Background(), adminEmail, os.Getenv("BOOTSTRAP_ADMIN_PASSWORD")); err != nil {
+			log.Fatal("Admin bootstrap failed:", err)
+		}
+	}
+
+	// Clients für /introspect, z.B. "payment-service:secret,checkout-service:secret"
+	clientRepo := mongoAdapter.NewClientRepository(db)
+	clientService := service.NewClientService(clientRepo, keys, eventPublisher, time.Duration(serviceTokenExpiry)*time.Second)
+	if err := clientService.SeedIntrospectionClients(os.Getenv("INTROSPECTION_CLIENTS")); err != nil {
+		log.Fatal("Client seeding failed:", err)
+	}
+
+	// OIDC-Provider nur mit OIDC_ISSUER; ID-Tokens müssen für die Clients
+	// prüfbar sein, daher nicht mit dem gemeinsamen HS256-Secret.
+	var oidcService *service.OIDCService
+	if issuer := os.Getenv("OIDC_ISSUER"); issuer != "" {
+		if !keys.Asymmetric() {
+			log.Fatal("OIDC_ISSUER requires JWT_SIGNING_ALG RS256 or EdDSA")
+		}
+		oidcService = service.NewOIDCService(authService, clientRepo, mongoAdapter.NewConsentRepository(db),
+			mongoAdapter.NewAuthorizationCodeRepository(db), service.OIDCConfig{
+				Issuer:     issuer,
+				CodeExpiry: time.Duration(oidcCodeExpiry) * time.Second,
+			})
+	}
 
 	router := gin.Default()
 
 	// Einfaches CORS Middleware
 	router.Use(func(c *gin.Context) {
 		c.Header("Access-Control-Allow-Origin", "*")
-		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
+		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
 		c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Authorization")
 		
 		if c.Request.Method == "OPTIONS" {
@@ -62,6 +282,121 @@ func main() {
 
 	router.POST("/register", handler.Register)
 	router.POST("/login", handler.Login)
+	router.POST("/token/refresh", handler.Refresh)
+	router.GET("/.well-known/jwks.json", handler.JWKS)
+	router.POST("/logout", httpAdapter.RequireAuth(authService), handler.Logout)
+
+	introspectionHandler := httpAdapter.NewIntrospectionHandler(authService, clientService)
+	router.POST("/introspect", introspectionHandler.Introspect)
+
+	clientHandler := httpAdapter.NewClientHandler(clientService, oidcService)
+	router.POST("/token", clientHandler.Token)
+
+	if oidcService != nil {
+		oidcHandler := httpAdapter.NewOIDCHandler(oidcService)
+		router.GET("/.well-known/openid-configuration", oidcHandler.Discovery)
+		router.GET("/authorize", oidcHandler.Authorize)
+		router.POST("/authorize", oidcHandler.AuthorizeSubmit)
+		router.POST("/authorize/consent", oidcHandler.Consent)
+		router.GET("/userinfo", oidcHandler.UserInfo)
+		router.POST("/userinfo", oidcHandler.UserInfo)
+	}
+
+	passwordHandler := httpAdapter.NewPasswordHandler(passwordService)
+	router.POST("/password/forgot", passwordHandler.Forgot)
+	router.POST("/password/reset", passwordHandler.Reset)
+
+	verificationHandler := httpAdapter.NewVerificationHandler(verificationService)
+	router.POST("/verify-email", verificationHandler.Verify)
+	router.POST("/verify-email/resend", verificationHandler.Resend)
+
+	profileService := service.NewProfileService(repo, oneTimeTokens, mailer, authService, verificationExpiry, appBaseURL)
+	profileHandler := httpAdapter.NewProfileHandler(profileService)
+	router.POST("/confirm-email", profileHandler.ConfirmEmail)
+	me := router.Group("/me")
+	me.Use(httpAdapter.RequireAuth(authService))
+	me.GET("", profileHandler.Get)
+	me.PATCH("", profileHandler.Update)
+	me.POST("/password", profileHandler.ChangePassword)
+	me.POST("/email", profileHandler.ChangeEmail)
+	sessionHandler := httpAdapter.NewSessionHandler(authService)
+	me.GET("/sessions", sessionHandler.List)
+	me.DELETE("/sessions/:id", sessionHandler.Revoke)
+
+	// Zwei-Faktor: Einrichtung auch mit mfa_pending Token, damit Admins beim
+	// ersten Login TOTP einrichten können
+	mfaHandler := httpAdapter.NewMFAHandler(authService)
+	router.POST("/mfa/verify", mfaHandler.Verify)
+	mfaSetup := router.Group("/mfa/totp")
+	mfaSetup.Use(httpAdapter.RequireAuth(authService, domain.TokenTypeAccess, domain.TokenTypeMFAPending))
+	mfaSetup.POST("/enroll", mfaHandler.Enroll)
+	mfaSetup.POST("/activate", mfaHandler.Activate)
+	router.POST("/mfa/totp/disable", httpAdapter.RequireAuth(authService, domain.TokenTypeAccess), mfaHandler.Disable)
+
+	userService := service.NewUserService(repo, authService, passwordService)
+	adminHandler := httpAdapter.NewAdminHandler(authService, roleService, userService, service.NewAuditService(auditRepo))
+	admin := router.Group("/admin")
+	admin.Use(httpAdapter.RequireAuth(authService, domain.TokenTypeAccess))
+	canReadUsers := httpAdapter.RequirePermission(domain.PermUsersRead)
+	canWriteUsers := httpAdapter.RequirePermission(domain.PermUsersWrite)
+	canManageRoles := httpAdapter.RequirePermission(domain.PermRolesManage)
+	canManageClients := httpAdapter.RequirePermission(domain.PermClientsManage)
+	admin.GET("/users", canReadUsers, adminHandler.ListUsers)
+	admin.GET("/users/:id", canReadUsers, adminHandler.GetUser)
+	admin.POST("/users/:id/disable", canWriteUsers, adminHandler.DisableUser)
+	admin.POST("/users/:id/enable", canWriteUsers, adminHandler.EnableUser)
+	admin.POST("/users/:id/password-reset", canWriteUsers, adminHandler.ForcePasswordReset)
+	admin.DELETE("/users/:id", canWriteUsers, adminHandler.DeleteUser)
+	admin.POST("/users/:id/role", canManageRoles, adminHandler.GrantRole)
+	admin.DELETE("/users/:id/role", canManageRoles, adminHandler.RevokeRole)
+	admin.GET("/users/:id/role-changes", httpAdapter.RequireAnyPermission(domain.PermRolesManage, domain.PermAuditRead), adminHandler.RoleHistory)
+	admin.POST("/users/:id/unlock", canWriteUsers, adminHandler.UnlockUser)
+	admin.POST("/users/:id/revoke-tokens", canWriteUsers, adminHandler.RevokeTokens)
+	admin.GET("/users/:id/sessions", canReadUsers, adminHandler.ListSessions)
+	admin.DELETE("/users/:id/sessions", canWriteUsers, adminHandler.TerminateSessions)
+	admin.GET("/audit", httpAdapter.RequirePermission(domain.PermAuditRead), adminHandler.AuditLog)
+
+	// DSGVO: Auskunft und Löschung über alle Services
+	var services []string
+	for _, name := range strings.Split(dataRequestServices, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			services = append(services, name)
+		}
+	}
+	dataRequestService := service.NewDataRequestService(mongoAdapter.NewDataRequestRepository(db), repo, auditRepo, authService, service.DataRequestConfig{
+		Services:  services,
+		Retention: time.Duration(dataRequestRetention) * 24 * time.Hour,
+	})
+	go func() {
+		if err := kafkaAdapter.NewDataRequestConsumer(kafkaBroker).StartConsuming(context.Background(), dataRequestService); err != nil {
+			log.Printf("Data request consumer stopped: %v", err)
+		}
+	}()
+	dataRequestHandler := httpAdapter.NewDataRequestHandler(dataRequestService)
+	admin.POST("/users/:id/data-requests", canWriteUsers, dataRequestHandler.Start)
+	admin.GET("/data-requests/:id", canReadUsers, dataRequestHandler.Get)
+	admin.GET("/data-requests/:id/export", canWriteUsers, dataRequestHandler.Download)
+	admin.GET("/clients", canManageClients, clientHandler.List)
+	admin.POST("/clients", canManageClients, clientHandler.Create)
+	admin.POST("/clients/:id/rotate", canManageClients, clientHandler.Rotate)
+	admin.DELETE("/clients/:id", canManageClients, clientHandler.Revoke)
+
+	// gRPC-API für interne Services auf eigenem Port, mit grpc.health.v1
+	grpcListener, err := net.Listen("tcp", ":"+strconv.Itoa(grpcPort))
+	if err != nil {
+		log.Fatal("gRPC listen failed:", err)
+	}
+	grpcServer := grpc.NewServer(grpc.UnaryInterceptor(grpcAdapter.ClientAuthInterceptor(clientService)))
+	authpb.RegisterAuthServiceServer(grpcServer, grpcAdapter.NewAuthServer(authService, userService))
+	healthServer := health.NewServer()
+	healthpb.RegisterHealthServer(grpcServer, healthServer)
+	healthServer.SetServingStatus(authpb.AuthService_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+	go func() {
+		log.Printf("gRPC-API läuft auf Port %d...", grpcPort)
+		if err := grpcServer.Serve(grpcListener); err != nil {
+			log.Printf("gRPC server stopped: %v", err)
+		}
+	}()
 
 	log.Println("Auth Service läuft auf Port 8081...")
 	router.Run(":8081")
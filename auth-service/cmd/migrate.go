@@ -0,0 +1,61 @@
+package main
+
+import (
+	mongoAdapter "auth-service/internal/adapters/mongo"
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// runMigrate bringt bestehende Daten auf den aktuellen Stand:
+//
+//	auth-service migrate [-dry-run]
+//
+// E-Mail-Adressen werden normalisiert und Duplikate gemeldet. Erst wenn es
+// keine mehr gibt, wird der eindeutige Index angelegt. Exit-Code 1 bei
+// Duplikaten oder Fehlern.
+func runMigrate(db *mongo.Database, collection string, args []string) int {
+	flags := flag.NewFlagSet("migrate", flag.ExitOnError)
+	dryRun := flags.Bool("dry-run", false, "only report, do not change anything")
+	flags.Parse(args)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	report, err := mongoAdapter.NormalizeEmails(ctx, db, collection, *dryRun)
+	if err != nil {
+		log.Printf("Email migration failed: %v", err)
+		return 1
+	}
+	verb := "Normalized"
+	if *dryRun {
+		verb = "Would normalize"
+	}
+	fmt.Printf("%s %d email addresses\n", verb, report.Normalized)
+
+	if len(report.Duplicates) > 0 {
+		fmt.Printf("Found %d duplicate email addresses, merge or delete these accounts and run migrate again:\n", len(report.Duplicates))
+		for _, d := range report.Duplicates {
+			fmt.Printf("  %s\n", d.Email)
+			for _, u := range d.Users {
+				fmt.Printf("    id=%s email=%q role=%s verified=%t created_at=%s\n",
+					u.ID, u.Email, u.Role, u.Verified, u.CreatedAt.Format(time.RFC3339))
+			}
+		}
+		return 1
+	}
+	if *dryRun {
+		return 0
+	}
+
+	if err := mongoAdapter.EnsureEmailIndex(ctx, db, collection); err != nil {
+		log.Printf("Failed to create unique email index: %v", err)
+		return 1
+	}
+	fmt.Println("Unique email index is in place")
+	return 0
+}
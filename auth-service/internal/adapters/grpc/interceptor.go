@@ -0,0 +1,77 @@
+package grpc
+
+import (
+	"auth-service/internal/domain"
+	"auth-service/internal/service"
+	"auth-service/pkg/authpb"
+	"context"
+	"encoding/base64"
+	"errors"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// methodScopes legt fest, welche Methoden einen Client mit welchem Scope
+// brauchen. Alle anderen (Register, Login, Health-Checks) sind öffentlich wie
+// die entsprechenden HTTP-Endpunkte.
+var methodScopes = map[string]string{
+	authpb.AuthService_ValidateToken_FullMethodName: domain.ScopeIntrospect,
+	authpb.AuthService_GetUser_FullMethodName:       domain.ScopeUsersRead,
+	authpb.AuthService_ListUsers_FullMethodName:     domain.ScopeUsersRead,
+}
+
+type clientContextKey struct{}
+
+// ClientAuthInterceptor prüft die Client-Zugangsdaten aus dem Metadatum
+// "authorization" ("Basic base64(client_id:client_secret)"). Bei öffentlichen
+// Methoden sind sie optional; ein angemeldeter Client wird im Context
+// abgelegt, damit seine Angaben zum Endnutzer übernommen werden.
+func ClientAuthInterceptor(clients *service.ClientService) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		scope, protected := methodScopes[info.FullMethod]
+		clientID, secret := basicCredentials(ctx)
+		if !protected && clientID == "" {
+			return handler(ctx, req)
+		}
+		client, err := clients.AuthenticateClient(clientID, secret, scope)
+		if err != nil {
+			if errors.Is(err, service.ErrInsufficientScope) {
+				return nil, status.Error(codes.PermissionDenied, err.Error())
+			}
+			return nil, status.Error(codes.Unauthenticated, err.Error())
+		}
+		return handler(context.WithValue(ctx, clientContextKey{}, client), req)
+	}
+}
+
+// authenticatedClient liefert den per ClientAuthInterceptor angemeldeten
+// Client, falls es einen gibt.
+func authenticatedClient(ctx context.Context) (*domain.Client, bool) {
+	client, ok := ctx.Value(clientContextKey{}).(*domain.Client)
+	return client, ok
+}
+
+func basicCredentials(ctx context.Context) (string, string) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return "", ""
+	}
+	values := md.Get("authorization")
+	if len(values) == 0 {
+		return "", ""
+	}
+	encoded, ok := strings.CutPrefix(values[0], "Basic ")
+	if !ok {
+		return "", ""
+	}
+	decoded, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", ""
+	}
+	clientID, secret, _ := strings.Cut(string(decoded), ":")
+	return clientID, secret
+}
@@ -0,0 +1,179 @@
+package grpc
+
+import (
+	"auth-service/internal/domain"
+	"auth-service/internal/service"
+	"auth-service/pkg/authpb"
+	"context"
+	"errors"
+	"log"
+	"net"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// AuthServer bietet Register, Login, Token-Prüfung und Userabfragen über gRPC
+// an. Es nutzt dieselben Services wie die HTTP-Handler.
+type AuthServer struct {
+	authpb.UnimplementedAuthServiceServer
+	auth  *service.AuthService
+	users *service.UserService
+}
+
+func NewAuthServer(auth *service.AuthService, users *service.UserService) *AuthServer {
+	return &AuthServer{auth: auth, users: users}
+}
+
+func (s *AuthServer) Register(ctx context.Context, req *authpb.RegisterRequest) (*authpb.RegisterResponse, error) {
+	if err := s.auth.Register(ctx, req.GetEmail(), req.GetPassword(), clientIP(ctx, req.GetClientIp())); err != nil {
+		var policyErr *service.PasswordPolicyError
+		switch {
+		case errors.As(err, &policyErr):
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		case errors.Is(err, service.ErrUserExists):
+			return nil, status.Error(codes.AlreadyExists, err.Error())
+		default:
+			log.Printf("gRPC Register failed: %v", err)
+			return nil, status.Error(codes.Internal, "registration failed")
+		}
+	}
+	return &authpb.RegisterResponse{}, nil
+}
+
+func (s *AuthServer) Login(ctx context.Context, req *authpb.LoginRequest) (*authpb.LoginResponse, error) {
+	tokens, err := s.auth.Login(ctx, req.GetEmail(), req.GetPassword(), clientIP(ctx, req.GetClientIp()), req.GetUserAgent())
+	if err != nil {
+		var blocked *service.LoginBlockedError
+		switch {
+		case errors.As(err, &blocked):
+			return nil, status.Error(codes.ResourceExhausted, err.Error())
+		case errors.Is(err, service.ErrInvalidCredentials):
+			return nil, status.Error(codes.Unauthenticated, err.Error())
+		case errors.Is(err, service.ErrEmailNotVerified), errors.Is(err, service.ErrAccountDisabled),
+			errors.Is(err, service.ErrPasswordResetRequired):
+			return nil, status.Error(codes.PermissionDenied, err.Error())
+		default:
+			log.Printf("gRPC Login failed: %v", err)
+			return nil, status.Error(codes.Internal, "token error")
+		}
+	}
+	return &authpb.LoginResponse{
+		AccessToken:           tokens.AccessToken,
+		RefreshToken:          tokens.RefreshToken,
+		ExpiresIn:             int64(tokens.ExpiresIn),
+		Restricted:            tokens.Restricted,
+		MfaRequired:           tokens.MFARequired,
+		MfaToken:              tokens.MFAToken,
+		MfaEnrollmentRequired: tokens.MFAEnrollmentRequired,
+	}, nil
+}
+
+func (s *AuthServer) ValidateToken(ctx context.Context, req *authpb.ValidateTokenRequest) (*authpb.ValidateTokenResponse, error) {
+	if req.GetToken() == "" {
+		return nil, status.Error(codes.InvalidArgument, "token is required")
+	}
+	result := s.auth.Introspect(req.GetToken())
+	if !result.Active {
+		return &authpb.ValidateTokenResponse{}, nil
+	}
+	resp := &authpb.ValidateTokenResponse{
+		Active:      true,
+		UserId:      result.UserID,
+		Email:       result.Email,
+		Role:        result.Role,
+		Permissions: result.Permissions,
+		Type:        result.Type,
+		Jti:         result.JTI,
+		ExpiresAt:   timestamppb.New(time.Unix(result.ExpiresAt, 0)),
+	}
+	if result.IssuedAt != 0 {
+		resp.IssuedAt = timestamppb.New(time.Unix(result.IssuedAt, 0))
+	}
+	return resp, nil
+}
+
+func (s *AuthServer) GetUser(ctx context.Context, req *authpb.GetUserRequest) (*authpb.User, error) {
+	if req.GetId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "id is required")
+	}
+	user, err := s.users.GetUser(ctx, req.GetId())
+	if err != nil {
+		if errors.Is(err, service.ErrUserNotFound) {
+			return nil, status.Error(codes.NotFound, err.Error())
+		}
+		return nil, status.Error(codes.Internal, "failed to load user")
+	}
+	return toUser(user), nil
+}
+
+func (s *AuthServer) ListUsers(ctx context.Context, req *authpb.ListUsersRequest) (*authpb.ListUsersResponse, error) {
+	filter := domain.UserFilter{
+		Role:     req.GetRole(),
+		Status:   req.GetStatus(),
+		Page:     int(req.GetPage()),
+		PageSize: int(req.GetPageSize()),
+	}
+	if req.CreatedAfter != nil {
+		t := req.GetCreatedAfter().AsTime()
+		filter.CreatedAfter = &t
+	}
+	if req.CreatedBefore != nil {
+		t := req.GetCreatedBefore().AsTime()
+		filter.CreatedBefore = &t
+	}
+
+	page, err := s.users.ListUsers(ctx, filter)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidUserFilter) {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		return nil, status.Error(codes.Internal, "failed to list users")
+	}
+	resp := &authpb.ListUsersResponse{
+		Users:    make([]*authpb.User, 0, len(page.Users)),
+		Total:    page.Total,
+		Page:     int32(page.Page),
+		PageSize: int32(page.PageSize),
+	}
+	for i := range page.Users {
+		resp.Users = append(resp.Users, toUser(&page.Users[i]))
+	}
+	return resp, nil
+}
+
+func toUser(u *domain.User) *authpb.User {
+	return &authpb.User{
+		Id:                u.ID,
+		Email:             u.Email,
+		Role:              u.Role,
+		Permissions:       domain.PermissionsForRole(u.Role),
+		Status:            u.Status(),
+		Verified:          u.Verified,
+		MfaEnabled:        u.MFAEnabled,
+		DisplayName:       u.DisplayName,
+		PreferredCurrency: u.PreferredCurrency,
+		CreatedAt:         timestamppb.New(u.CreatedAt),
+	}
+}
+
+// clientIP übernimmt die mitgeschickte Adresse des Endnutzers nur von
+// angemeldeten Clients, sonst könnte jeder Aufrufer die Drosselung pro IP
+// umgehen. Ansonsten gilt die Adresse der Verbindung.
+func clientIP(ctx context.Context, forwarded string) string {
+	if _, trusted := authenticatedClient(ctx); forwarded != "" && trusted {
+		return forwarded
+	}
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+	return host
+}
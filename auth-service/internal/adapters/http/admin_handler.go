@@ -0,0 +1,253 @@
+package http
+
+import (
+	"auth-service/internal/domain"
+	"auth-service/internal/service"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AdminHandler struct {
+	auth  *service.AuthService
+	roles *service.RoleService
+	users *service.UserService
+	audit *service.AuditService
+}
+
+func NewAdminHandler(auth *service.AuthService, roles *service.RoleService, users *service.UserService, audit *service.AuditService) *AdminHandler {
+	return &AdminHandler{auth: auth, roles: roles, users: users, audit: audit}
+}
+
+// ListUsers unterstützt ?role=, ?status=, ?created_after=, ?created_before=
+// (RFC 3339 oder YYYY-MM-DD), ?page= und ?page_size=.
+func (h *AdminHandler) ListUsers(c *gin.Context) {
+	filter := domain.UserFilter{
+		Role:   c.Query("role"),
+		Status: c.Query("status"),
+	}
+	var err error
+	if filter.CreatedAfter, err = parseDateQuery(c.Query("created_after")); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid created_after"})
+		return
+	}
+	if filter.CreatedBefore, err = parseDateQuery(c.Query("created_before")); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid created_before"})
+		return
+	}
+	filter.Page, _ = strconv.Atoi(c.Query("page"))
+	filter.PageSize, _ = strconv.Atoi(c.Query("page_size"))
+
+	page, err := h.users.ListUsers(c.Request.Context(), filter)
+	if err != nil {
+		respondUserError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, page)
+}
+
+func (h *AdminHandler) GetUser(c *gin.Context) {
+	user, err := h.users.GetUser(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		respondUserError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, user)
+}
+
+func (h *AdminHandler) DisableUser(c *gin.Context) {
+	claims, _ := getClaims(c)
+	user, err := h.users.DisableUser(c.Request.Context(), claims.UserID, c.Param("id"), c.ClientIP())
+	if err != nil {
+		respondUserError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, user)
+}
+
+func (h *AdminHandler) EnableUser(c *gin.Context) {
+	claims, _ := getClaims(c)
+	user, err := h.users.EnableUser(c.Request.Context(), claims.UserID, c.Param("id"))
+	if err != nil {
+		respondUserError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, user)
+}
+
+func (h *AdminHandler) ForcePasswordReset(c *gin.Context) {
+	claims, _ := getClaims(c)
+	if err := h.users.ForcePasswordReset(c.Request.Context(), claims.UserID, c.Param("id"), c.ClientIP()); err != nil {
+		respondUserError(c, err)
+		return
+	}
+	c.JSON(http.StatusAccepted, gin.H{"message": "password reset required, reset link sent"})
+}
+
+func (h *AdminHandler) DeleteUser(c *gin.Context) {
+	claims, _ := getClaims(c)
+	if err := h.users.DeleteUser(c.Request.Context(), claims.UserID, c.Param("id"), c.ClientIP()); err != nil {
+		respondUserError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (h *AdminHandler) GrantRole(c *gin.Context) {
+	var req struct {
+		Role string `json:"role" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	claims, _ := getClaims(c)
+	user, err := h.roles.GrantRole(c.Request.Context(), claims.UserID, c.Param("id"), req.Role, c.ClientIP())
+	if err != nil {
+		respondRoleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, user)
+}
+
+func (h *AdminHandler) RevokeRole(c *gin.Context) {
+	claims, _ := getClaims(c)
+	user, err := h.roles.RevokeRole(c.Request.Context(), claims.UserID, c.Param("id"), c.ClientIP())
+	if err != nil {
+		respondRoleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, user)
+}
+
+func (h *AdminHandler) RoleHistory(c *gin.Context) {
+	changes, err := h.roles.History(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load role history"})
+		return
+	}
+	c.JSON(http.StatusOK, changes)
+}
+
+func (h *AdminHandler) UnlockUser(c *gin.Context) {
+	if err := h.auth.UnlockUser(c.Request.Context(), c.Param("id")); err != nil {
+		if errors.Is(err, service.ErrUserNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to unlock user"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "user unlocked"})
+}
+
+func (h *AdminHandler) RevokeTokens(c *gin.Context) {
+	claims, _ := getClaims(c)
+	if err := h.auth.RevokeAllTokens(c.Request.Context(), c.Param("id"), claims.UserID, c.ClientIP()); err != nil {
+		if errors.Is(err, service.ErrUserNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to revoke tokens"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "tokens revoked"})
+}
+
+func (h *AdminHandler) ListSessions(c *gin.Context) {
+	sessions, err := h.auth.ListSessions(c.Request.Context(), c.Param("id"), "")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load sessions"})
+		return
+	}
+	c.JSON(http.StatusOK, sessions)
+}
+
+// TerminateSessions meldet den User auf allen Geräten ab. Dabei werden wie bei
+// RevokeTokens auch alle noch gültigen Access-Tokens gesperrt.
+func (h *AdminHandler) TerminateSessions(c *gin.Context) {
+	claims, _ := getClaims(c)
+	if err := h.auth.RevokeAllTokens(c.Request.Context(), c.Param("id"), claims.UserID, c.ClientIP()); err != nil {
+		if errors.Is(err, service.ErrUserNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to terminate sessions"})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func respondRoleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrInvalidRole), errors.Is(err, service.ErrSelfRoleChange):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrUserNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update role"})
+	}
+}
+
+// AuditLog durchsucht das Audit-Log. Filter: ?event_type=, ?user_id=,
+// ?actor_id=, ?email=, ?ip=, ?from=, ?to= (RFC 3339 oder YYYY-MM-DD),
+// ?page= und ?page_size=.
+func (h *AdminHandler) AuditLog(c *gin.Context) {
+	filter := domain.AuditFilter{
+		EventType: c.Query("event_type"),
+		UserID:    c.Query("user_id"),
+		ActorID:   c.Query("actor_id"),
+		Email:     c.Query("email"),
+		IP:        c.Query("ip"),
+	}
+	var err error
+	if filter.From, err = parseDateQuery(c.Query("from")); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid from"})
+		return
+	}
+	if filter.To, err = parseDateQuery(c.Query("to")); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid to"})
+		return
+	}
+	filter.Page, _ = strconv.Atoi(c.Query("page"))
+	filter.PageSize, _ = strconv.Atoi(c.Query("page_size"))
+
+	page, err := h.audit.Search(c.Request.Context(), filter)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidAuditFilter) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load audit log"})
+		return
+	}
+	c.JSON(http.StatusOK, page)
+}
+
+func respondUserError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrInvalidUserFilter), errors.Is(err, service.ErrSelfAccountChange):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrUserNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "user operation failed"})
+	}
+}
+
+func parseDateQuery(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		if t, err = time.Parse("2006-01-02", value); err != nil {
+			return nil, err
+		}
+	}
+	return &t, nil
+}
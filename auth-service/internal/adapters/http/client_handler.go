@@ -0,0 +1,119 @@
+package http
+
+import (
+	"auth-service/internal/service"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ClientHandler struct {
+	service *service.ClientService
+	oidc    *service.OIDCService
+}
+
+// NewClientHandler: oidc ist nil, wenn der OIDC-Modus nicht aktiv ist.
+func NewClientHandler(s *service.ClientService, oidc *service.OIDCService) *ClientHandler {
+	return &ClientHandler{service: s, oidc: oidc}
+}
+
+// Token implementiert den client_credentials Grant und, im OIDC-Modus, den
+// authorization_code Grant (form-encodiert, Zugangsdaten per Basic Auth oder
+// im Body).
+func (h *ClientHandler) Token(c *gin.Context) {
+	c.Header("Cache-Control", "no-store")
+	grantType := c.PostForm("grant_type")
+	if grantType == "authorization_code" && h.oidc != nil {
+		exchangeAuthorizationCode(c, h.oidc)
+		return
+	}
+	if grantType != "client_credentials" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "unsupported_grant_type"})
+		return
+	}
+
+	clientID, secret := clientCredentials(c)
+	token, err := h.service.IssueServiceToken(clientID, secret, c.PostForm("scope"))
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidClient):
+			c.Header("WWW-Authenticate", `Basic realm="token"`)
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid_client"})
+		case errors.Is(err, service.ErrInvalidScope):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_scope"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Token error"})
+		}
+		return
+	}
+	c.JSON(http.StatusOK, token)
+}
+
+func (h *ClientHandler) List(c *gin.Context) {
+	clients, err := h.service.ListClients()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load clients"})
+		return
+	}
+	c.JSON(http.StatusOK, clients)
+}
+
+func (h *ClientHandler) Create(c *gin.Context) {
+	var req struct {
+		Name         string   `json:"name" binding:"required"`
+		Scopes       []string `json:"scopes" binding:"required"`
+		RedirectURIs []string `json:"redirect_uris"`
+		Public       bool     `json:"public"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	claims, _ := getClaims(c)
+	creds, err := h.service.CreateClient(claims.UserID, req.Name, req.Scopes, req.RedirectURIs, req.Public)
+	if err != nil {
+		respondClientError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, creds)
+}
+
+func (h *ClientHandler) Rotate(c *gin.Context) {
+	creds, err := h.service.RotateSecret(c.Param("id"))
+	if err != nil {
+		respondClientError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, creds)
+}
+
+func (h *ClientHandler) Revoke(c *gin.Context) {
+	claims, _ := getClaims(c)
+	if err := h.service.RevokeClient(claims.UserID, c.Param("id")); err != nil {
+		respondClientError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "client revoked"})
+}
+
+func respondClientError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrInvalidScope), errors.Is(err, service.ErrClientNameMissing),
+		errors.Is(err, service.ErrInvalidRedirectURI), errors.Is(err, service.ErrPublicClientScope):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrClientNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "client operation failed"})
+	}
+}
+
+// clientCredentials liest client_id und Secret aus Basic Auth oder dem Body.
+func clientCredentials(c *gin.Context) (string, string) {
+	if id, secret, ok := c.Request.BasicAuth(); ok {
+		return id, secret
+	}
+	return c.PostForm("client_id"), c.PostForm("client_secret")
+}
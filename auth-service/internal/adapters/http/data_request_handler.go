@@ -0,0 +1,71 @@
+package http
+
+import (
+	"auth-service/internal/service"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DataRequestHandler bedient Auskunfts- und Löschanfragen unter /admin.
+type DataRequestHandler struct {
+	service *service.DataRequestService
+}
+
+func NewDataRequestHandler(s *service.DataRequestService) *DataRequestHandler {
+	return &DataRequestHandler{service: s}
+}
+
+// Start erwartet {"type": "export"} oder {"type": "erasure"}.
+func (h *DataRequestHandler) Start(c *gin.Context) {
+	var req struct {
+		Type string `json:"type" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	claims, _ := getClaims(c)
+	request, err := h.service.Start(c.Request.Context(), claims.UserID, c.Param("id"), req.Type, c.ClientIP())
+	if err != nil {
+		respondDataRequestError(c, err)
+		return
+	}
+	c.JSON(http.StatusAccepted, request)
+}
+
+func (h *DataRequestHandler) Get(c *gin.Context) {
+	request, err := h.service.Get(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		respondDataRequestError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, request)
+}
+
+func (h *DataRequestHandler) Download(c *gin.Context) {
+	archive, err := h.service.ExportArchive(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		respondDataRequestError(c, err)
+		return
+	}
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="export-%s.zip"`, c.Param("id")))
+	c.Header("Cache-Control", "no-store")
+	c.Data(http.StatusOK, "application/zip", archive)
+}
+
+func respondDataRequestError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrInvalidDataRequest), errors.Is(err, service.ErrSelfAccountChange):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrUserNotFound), errors.Is(err, service.ErrDataRequestNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrDataRequestNotReady):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "data request failed"})
+	}
+}
@@ -0,0 +1,44 @@
+package http
+
+import (
+	"auth-service/internal/domain"
+	"auth-service/internal/service"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type IntrospectionHandler struct {
+	auth    *service.AuthService
+	clients *service.ClientService
+}
+
+func NewIntrospectionHandler(auth *service.AuthService, clients *service.ClientService) *IntrospectionHandler {
+	return &IntrospectionHandler{auth: auth, clients: clients}
+}
+
+// Introspect erwartet wie RFC 7662 ein form-encodiertes "token" und die
+// Client-Zugangsdaten per HTTP Basic Auth (alternativ client_id/client_secret
+// im Body).
+func (h *IntrospectionHandler) Introspect(c *gin.Context) {
+	clientID, secret := clientCredentials(c)
+	if _, err := h.clients.AuthenticateClient(clientID, secret, domain.ScopeIntrospect); err != nil {
+		if errors.Is(err, service.ErrInsufficientScope) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		c.Header("WWW-Authenticate", `Basic realm="introspect"`)
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	token := c.PostForm("token")
+	if token == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "token is required"})
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, h.auth.Introspect(token))
+}
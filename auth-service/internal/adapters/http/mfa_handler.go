@@ -0,0 +1,101 @@
+package http
+
+import (
+	"auth-service/internal/service"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type MFAHandler struct {
+	service *service.AuthService
+}
+
+func NewMFAHandler(s *service.AuthService) *MFAHandler {
+	return &MFAHandler{service: s}
+}
+
+func (h *MFAHandler) Enroll(c *gin.Context) {
+	claims, _ := getClaims(c)
+	enrollment, err := h.service.EnrollTOTP(c.Request.Context(), claims)
+	if err != nil {
+		respondMFAError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, enrollment)
+}
+
+func (h *MFAHandler) Activate(c *gin.Context) {
+	var req struct {
+		Code string `json:"code" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	claims, _ := getClaims(c)
+	activation, err := h.service.ActivateTOTP(c.Request.Context(), claims, req.Code, c.ClientIP(), c.Request.UserAgent())
+	if err != nil {
+		respondMFAError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, activation)
+}
+
+func (h *MFAHandler) Verify(c *gin.Context) {
+	var req struct {
+		MFAToken string `json:"mfa_token" binding:"required"`
+		Code     string `json:"code" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	tokens, err := h.service.VerifyMFA(c.Request.Context(), req.MFAToken, req.Code, c.ClientIP(), c.Request.UserAgent())
+	if err != nil {
+		respondMFAError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, tokens)
+}
+
+func (h *MFAHandler) Disable(c *gin.Context) {
+	var req struct {
+		Code string `json:"code" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	claims, _ := getClaims(c)
+	if err := h.service.DisableTOTP(c.Request.Context(), claims, req.Code); err != nil {
+		respondMFAError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "two-factor authentication disabled"})
+}
+
+func respondMFAError(c *gin.Context, err error) {
+	var blocked *service.LoginBlockedError
+	switch {
+	case errors.As(err, &blocked):
+		c.Header("Retry-After", strconv.Itoa(int(blocked.RetryAfter.Seconds())+1))
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrInvalidToken), errors.Is(err, service.ErrInvalidMFACode):
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrMFAMandatory):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrMFANotPending), errors.Is(err, service.ErrMFAAlreadyEnabled),
+		errors.Is(err, service.ErrMFAEnrollmentRequired):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrUserNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "two-factor operation failed"})
+	}
+}
@@ -0,0 +1,90 @@
+package http
+
+import (
+	"auth-service/internal/domain"
+	"auth-service/internal/service"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const contextClaimsKey = "claims"
+
+// RequireAuth prüft das Bearer-Token und legt die Claims im Context ab. Ohne
+// Angabe von tokenTypes werden normale und eingeschränkte Access-Tokens
+// akzeptiert.
+func RequireAuth(s *service.AuthService, tokenTypes ...string) gin.HandlerFunc {
+	if len(tokenTypes) == 0 {
+		tokenTypes = []string{domain.TokenTypeAccess, domain.TokenTypeUnverified}
+	}
+	return func(c *gin.Context) {
+		auth := c.GetHeader("Authorization")
+		parts := strings.SplitN(auth, " ", 2)
+		if len(parts) != 2 || strings.ToLower(parts[0]) != "bearer" {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "missing or invalid authorization header"})
+			return
+		}
+
+		claims, err := s.ValidateAccessToken(parts[1])
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+		if !containsString(tokenTypes, claims.Type) {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "token type not allowed"})
+			return
+		}
+		c.Set(contextClaimsKey, claims)
+		c.Next()
+	}
+}
+
+// RequirePermission verlangt alle angegebenen Berechtigungen und muss nach
+// RequireAuth laufen.
+func RequirePermission(permissions ...string) gin.HandlerFunc {
+	return requirePermissions(permissions, true)
+}
+
+// RequireAnyPermission verlangt mindestens eine der Berechtigungen.
+func RequireAnyPermission(permissions ...string) gin.HandlerFunc {
+	return requirePermissions(permissions, false)
+}
+
+func requirePermissions(permissions []string, all bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims, ok := getClaims(c)
+		if !ok || !hasPermissions(claims, permissions, all) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "insufficient permissions"})
+			return
+		}
+		c.Next()
+	}
+}
+
+func hasPermissions(claims *domain.Claims, permissions []string, all bool) bool {
+	for _, p := range permissions {
+		if claims.HasPermission(p) != all {
+			return !all
+		}
+	}
+	return all
+}
+
+func getClaims(c *gin.Context) (*domain.Claims, bool) {
+	v, ok := c.Get(contextClaimsKey)
+	if !ok {
+		return nil, false
+	}
+	claims, ok := v.(*domain.Claims)
+	return claims, ok
+}
+
+func containsString(list []string, v string) bool {
+	for _, s := range list {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,274 @@
+package http
+
+import (
+	"auth-service/internal/domain"
+	"auth-service/internal/service"
+	"errors"
+	"html/template"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// OIDCHandler stellt die OpenID-Connect-Endpunkte bereit. /authorize liefert
+// eine schlichte HTML-Seite für Anmeldung und Zustimmung.
+type OIDCHandler struct {
+	service *service.OIDCService
+}
+
+func NewOIDCHandler(s *service.OIDCService) *OIDCHandler {
+	return &OIDCHandler{service: s}
+}
+
+func (h *OIDCHandler) Discovery(c *gin.Context) {
+	c.JSON(http.StatusOK, h.service.Discovery())
+}
+
+// Authorize zeigt die Anmeldeseite, sofern Client und Parameter gültig sind.
+func (h *OIDCHandler) Authorize(c *gin.Context) {
+	req := authorizationRequest(c)
+	client, scopes, err := h.service.ValidateAuthorizationRequest(req)
+	if err != nil {
+		h.respondAuthorizeError(c, req, err)
+		return
+	}
+	renderAuthorizePage(c, http.StatusOK, authorizePage{Request: req, Client: client, Scopes: scopes})
+}
+
+// AuthorizeSubmit prüft die Zugangsdaten aus dem Formular. Je nach Ergebnis
+// folgt die MFA-Abfrage, die Zustimmungsseite oder direkt die Weiterleitung.
+func (h *OIDCHandler) AuthorizeSubmit(c *gin.Context) {
+	req := authorizationRequest(c)
+	if c.PostForm("action") == "cancel" {
+		if _, _, err := h.service.ValidateAuthorizationRequest(req); err != nil {
+			h.respondAuthorizeError(c, req, err)
+			return
+		}
+		c.Redirect(http.StatusSeeOther, h.service.ErrorRedirect(req, &service.OAuthError{Code: "access_denied"}))
+		return
+	}
+
+	page := authorizePage{Request: req, Email: c.PostForm("email")}
+	result, err := h.service.Authorize(c.Request.Context(), req, c.PostForm("email"), c.PostForm("password"), c.PostForm("mfa_code"), c.ClientIP())
+	if err != nil {
+		var oauthErr *service.OAuthError
+		var blocked *service.LoginBlockedError
+		switch {
+		case errors.Is(err, service.ErrUnknownOIDCClient), errors.Is(err, service.ErrInvalidRedirectURI), errors.As(err, &oauthErr):
+			h.respondAuthorizeError(c, req, err)
+			return
+		case errors.Is(err, service.ErrMFACodeRequired):
+			page.MFA = true
+		case errors.Is(err, service.ErrInvalidMFACode):
+			page.MFA = true
+			page.Error = "Der Code ist ungültig."
+		case errors.As(err, &blocked):
+			page.Error = "Zu viele Fehlversuche, bitte später erneut versuchen."
+		case errors.Is(err, service.ErrInvalidCredentials):
+			page.Error = "E-Mail oder Passwort ist falsch."
+		case errors.Is(err, service.ErrEmailNotVerified):
+			page.Error = "Bitte bestätige zuerst deine E-Mail-Adresse."
+		case errors.Is(err, service.ErrAccountDisabled):
+			page.Error = "Dieses Konto ist deaktiviert."
+		case errors.Is(err, service.ErrPasswordResetRequired):
+			page.Error = "Bitte setze zuerst ein neues Passwort."
+		case errors.Is(err, service.ErrMFAEnrollmentRequired):
+			page.Error = "Bitte richte zuerst die Zwei-Faktor-Authentifizierung ein."
+		default:
+			page.Error = "Die Anmeldung ist fehlgeschlagen."
+		}
+		page.Client, page.Scopes, _ = h.service.ValidateAuthorizationRequest(req)
+		status := http.StatusUnauthorized
+		if page.Error == "" {
+			status = http.StatusOK
+		}
+		renderAuthorizePage(c, status, page)
+		return
+	}
+
+	if result.ConsentTicket != "" {
+		renderAuthorizePage(c, http.StatusOK, authorizePage{
+			Request:       req,
+			Client:        result.Client,
+			Scopes:        result.Scopes,
+			ConsentTicket: result.ConsentTicket,
+		})
+		return
+	}
+	c.Redirect(http.StatusSeeOther, result.RedirectURL)
+}
+
+func (h *OIDCHandler) Consent(c *gin.Context) {
+	redirect, err := h.service.Consent(c.Request.Context(), c.PostForm("consent_ticket"), c.PostForm("action") == "approve")
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidToken) {
+			renderAuthorizePage(c, http.StatusBadRequest, authorizePage{Fatal: "Die Anfrage ist abgelaufen. Bitte starte die Anmeldung erneut."})
+			return
+		}
+		h.respondAuthorizeError(c, domain.AuthorizationRequest{}, err)
+		return
+	}
+	c.Redirect(http.StatusSeeOther, redirect)
+}
+
+// UserInfo akzeptiert nur Access-Tokens aus dem Code-Flow.
+func (h *OIDCHandler) UserInfo(c *gin.Context) {
+	token := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+	if token == "" || token == c.GetHeader("Authorization") {
+		c.Header("WWW-Authenticate", `Bearer realm="userinfo"`)
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid_token"})
+		return
+	}
+	info, err := h.service.UserInfo(c.Request.Context(), token)
+	if err != nil {
+		c.Header("WWW-Authenticate", `Bearer realm="userinfo", error="invalid_token"`)
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid_token"})
+		return
+	}
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, info)
+}
+
+// respondAuthorizeError leitet OAuth-Fehler an den Client weiter. Bei
+// unbekanntem Client oder falscher redirect_uri gibt es nur eine Fehlerseite.
+func (h *OIDCHandler) respondAuthorizeError(c *gin.Context, req domain.AuthorizationRequest, err error) {
+	var oauthErr *service.OAuthError
+	switch {
+	case errors.Is(err, service.ErrUnknownOIDCClient):
+		renderAuthorizePage(c, http.StatusBadRequest, authorizePage{Fatal: "Unbekannte Anwendung."})
+	case errors.Is(err, service.ErrInvalidRedirectURI):
+		renderAuthorizePage(c, http.StatusBadRequest, authorizePage{Fatal: "Die redirect_uri ist für diese Anwendung nicht registriert."})
+	case errors.As(err, &oauthErr) && req.RedirectURI != "":
+		c.Redirect(http.StatusFound, h.service.ErrorRedirect(req, oauthErr))
+	default:
+		renderAuthorizePage(c, http.StatusInternalServerError, authorizePage{Fatal: "Die Anmeldung ist fehlgeschlagen."})
+	}
+}
+
+// exchangeAuthorizationCode bedient grant_type=authorization_code auf /token.
+func exchangeAuthorizationCode(c *gin.Context, oidc *service.OIDCService) {
+	clientID, secret := clientCredentials(c)
+	tokens, err := oidc.ExchangeCode(c.Request.Context(), clientID, secret,
+		c.PostForm("code"), c.PostForm("redirect_uri"), c.PostForm("code_verifier"))
+	if err != nil {
+		var oauthErr *service.OAuthError
+		if errors.As(err, &oauthErr) {
+			status := http.StatusBadRequest
+			if oauthErr.Code == "invalid_client" {
+				c.Header("WWW-Authenticate", `Basic realm="token"`)
+				status = http.StatusUnauthorized
+			}
+			body := gin.H{"error": oauthErr.Code}
+			if oauthErr.Description != "" {
+				body["error_description"] = oauthErr.Description
+			}
+			c.JSON(status, body)
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "server_error"})
+		return
+	}
+	c.JSON(http.StatusOK, tokens)
+}
+
+func authorizationRequest(c *gin.Context) domain.AuthorizationRequest {
+	// GET liefert die Parameter in der Query, das Formular per POST im Body
+	value := c.Query
+	if c.Request.Method == http.MethodPost {
+		value = c.PostForm
+	}
+	return domain.AuthorizationRequest{
+		ClientID:            value("client_id"),
+		RedirectURI:         value("redirect_uri"),
+		ResponseType:        value("response_type"),
+		Scope:               value("scope"),
+		State:               value("state"),
+		Nonce:               value("nonce"),
+		CodeChallenge:       value("code_challenge"),
+		CodeChallengeMethod: value("code_challenge_method"),
+		Prompt:              value("prompt"),
+	}
+}
+
+type authorizePage struct {
+	Request       domain.AuthorizationRequest
+	Client        *domain.Client
+	Scopes        []string
+	Email         string
+	MFA           bool
+	ConsentTicket string
+	Error         string
+	Fatal         string
+}
+
+var scopeDescriptions = map[string]string{
+	domain.ScopeOpenID:  "Deine Benutzer-ID",
+	domain.ScopeProfile: "Dein Anzeigename",
+	domain.ScopeEmail:   "Deine E-Mail-Adresse",
+}
+
+var authorizeTemplate = template.Must(template.New("authorize").Funcs(template.FuncMap{
+	"describe": func(scope string) string { return scopeDescriptions[scope] },
+}).Parse(`<!DOCTYPE html>
+<html lang="de">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>Anmelden – Cloud-Native Shop</title>
+<style>
+body { font-family: sans-serif; max-width: 24rem; margin: 4rem auto; padding: 0 1rem; }
+label, input, button { display: block; width: 100%; box-sizing: border-box; }
+input { margin: .25rem 0 1rem; padding: .5rem; }
+button { margin-top: .5rem; padding: .5rem; }
+.error { color: #b00020; }
+</style>
+</head>
+<body>
+{{if .Fatal}}
+<h1>Anmeldung nicht möglich</h1>
+<p class="error">{{.Fatal}}</p>
+{{else if .ConsentTicket}}
+<h1>Zugriff erlauben?</h1>
+<p><strong>{{.Client.Name}}</strong> möchte auf folgende Daten zugreifen:</p>
+<ul>{{range .Scopes}}<li>{{describe .}}</li>{{end}}</ul>
+<form method="post" action="authorize/consent">
+<input type="hidden" name="consent_ticket" value="{{.ConsentTicket}}">
+<button type="submit" name="action" value="approve">Erlauben</button>
+<button type="submit" name="action" value="deny">Ablehnen</button>
+</form>
+{{else}}
+<h1>Anmelden bei {{.Client.Name}}</h1>
+{{if .Error}}<p class="error">{{.Error}}</p>{{end}}
+<form method="post" action="authorize">
+<input type="hidden" name="client_id" value="{{.Request.ClientID}}">
+<input type="hidden" name="redirect_uri" value="{{.Request.RedirectURI}}">
+<input type="hidden" name="response_type" value="{{.Request.ResponseType}}">
+<input type="hidden" name="scope" value="{{.Request.Scope}}">
+<input type="hidden" name="state" value="{{.Request.State}}">
+<input type="hidden" name="nonce" value="{{.Request.Nonce}}">
+<input type="hidden" name="code_challenge" value="{{.Request.CodeChallenge}}">
+<input type="hidden" name="code_challenge_method" value="{{.Request.CodeChallengeMethod}}">
+<input type="hidden" name="prompt" value="{{.Request.Prompt}}">
+<label>E-Mail<input type="email" name="email" value="{{.Email}}" autocomplete="username" required></label>
+<label>Passwort<input type="password" name="password" autocomplete="current-password" required></label>
+{{if .MFA}}<label>Code aus der Authenticator-App oder Recovery-Code<input type="text" name="mfa_code" autocomplete="one-time-code" required autofocus></label>{{end}}
+<button type="submit" name="action" value="login">Anmelden</button>
+<button type="submit" name="action" value="cancel" formnovalidate>Abbrechen</button>
+</form>
+{{end}}
+</body>
+</html>
+`))
+
+func renderAuthorizePage(c *gin.Context, status int, page authorizePage) {
+	c.Header("Cache-Control", "no-store")
+	// Die Seite darf nicht in fremde Seiten eingebettet werden (Clickjacking)
+	c.Header("X-Frame-Options", "DENY")
+	c.Header("Content-Security-Policy", "default-src 'none'; style-src 'unsafe-inline'; frame-ancestors 'none'")
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	c.Status(status)
+	if err := authorizeTemplate.Execute(c.Writer, page); err != nil {
+		c.Error(err)
+	}
+}
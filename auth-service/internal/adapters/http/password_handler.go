@@ -0,0 +1,58 @@
+package http
+
+import (
+	"auth-service/internal/service"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PasswordHandler struct {
+	service *service.PasswordService
+}
+
+func NewPasswordHandler(s *service.PasswordService) *PasswordHandler {
+	return &PasswordHandler{service: s}
+}
+
+func (h *PasswordHandler) Forgot(c *gin.Context) {
+	var req struct {
+		Email string `json:"email" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.service.ForgotPassword(c.Request.Context(), req.Email); err != nil {
+		log.Printf("Password reset request failed: %v", err)
+	}
+
+	// Immer dieselbe Antwort, egal ob die Adresse existiert
+	c.JSON(http.StatusAccepted, gin.H{"message": "if the account exists, a reset link has been sent"})
+}
+
+func (h *PasswordHandler) Reset(c *gin.Context) {
+	var req struct {
+		Token    string `json:"token" binding:"required"`
+		Password string `json:"password" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.service.ResetPassword(c.Request.Context(), req.Token, req.Password, c.ClientIP()); err != nil {
+		var policyErr *service.PasswordPolicyError
+		if errors.Is(err, service.ErrInvalidResetToken) || errors.Is(err, service.ErrPasswordRequired) || errors.As(err, &policyErr) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to reset password"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "password updated"})
+}
@@ -0,0 +1,119 @@
+package http
+
+import (
+	"auth-service/internal/domain"
+	"auth-service/internal/service"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ProfileHandler struct {
+	service *service.ProfileService
+}
+
+func NewProfileHandler(s *service.ProfileService) *ProfileHandler {
+	return &ProfileHandler{service: s}
+}
+
+func (h *ProfileHandler) Get(c *gin.Context) {
+	claims, _ := getClaims(c)
+	user, err := h.service.GetProfile(c.Request.Context(), claims.UserID)
+	if err != nil {
+		respondProfileError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, user)
+}
+
+func (h *ProfileHandler) Update(c *gin.Context) {
+	var req domain.ProfileUpdate
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	claims, _ := getClaims(c)
+	user, err := h.service.UpdateProfile(c.Request.Context(), claims.UserID, req)
+	if err != nil {
+		respondProfileError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, user)
+}
+
+func (h *ProfileHandler) ChangePassword(c *gin.Context) {
+	var req struct {
+		CurrentPassword string `json:"current_password" binding:"required"`
+		NewPassword     string `json:"new_password" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	claims, _ := getClaims(c)
+	tokens, err := h.service.ChangePassword(c.Request.Context(), claims.UserID, req.CurrentPassword, req.NewPassword, c.ClientIP(), c.Request.UserAgent())
+	if err != nil {
+		respondProfileError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, tokens)
+}
+
+func (h *ProfileHandler) ChangeEmail(c *gin.Context) {
+	var req struct {
+		Email    string `json:"email" binding:"required"`
+		Password string `json:"password" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	claims, _ := getClaims(c)
+	if err := h.service.RequestEmailChange(c.Request.Context(), claims.UserID, req.Email, req.Password); err != nil {
+		respondProfileError(c, err)
+		return
+	}
+	c.JSON(http.StatusAccepted, gin.H{"message": "confirmation link sent to the new address"})
+}
+
+func (h *ProfileHandler) ConfirmEmail(c *gin.Context) {
+	var req struct {
+		Token string `json:"token" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.service.ConfirmEmailChange(c.Request.Context(), req.Token); err != nil {
+		respondProfileError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "email changed"})
+}
+
+func respondProfileError(c *gin.Context, err error) {
+	var policyErr *service.PasswordPolicyError
+	switch {
+	case errors.As(err, &policyErr):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrWrongPassword):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrEmailTaken):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrUserNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrInvalidDisplayName), errors.Is(err, service.ErrInvalidCurrency),
+		errors.Is(err, service.ErrInvalidAddress), errors.Is(err, service.ErrTooManyAddresses),
+		errors.Is(err, service.ErrMultipleDefaultAddress), errors.Is(err, service.ErrInvalidEmail),
+		errors.Is(err, service.ErrEmailUnchanged), errors.Is(err, service.ErrInvalidEmailChangeToken),
+		errors.Is(err, service.ErrPasswordRequired):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "profile update failed"})
+	}
+}
@@ -0,0 +1,42 @@
+package http
+
+import (
+	"auth-service/internal/service"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SessionHandler zeigt dem User seine angemeldeten Geräte unter /me/sessions.
+type SessionHandler struct {
+	service *service.AuthService
+}
+
+func NewSessionHandler(s *service.AuthService) *SessionHandler {
+	return &SessionHandler{service: s}
+}
+
+func (h *SessionHandler) List(c *gin.Context) {
+	claims, _ := getClaims(c)
+	sessions, err := h.service.ListSessions(c.Request.Context(), claims.UserID, claims.SessionID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load sessions"})
+		return
+	}
+	c.JSON(http.StatusOK, sessions)
+}
+
+func (h *SessionHandler) Revoke(c *gin.Context) {
+	claims, _ := getClaims(c)
+	err := h.service.RevokeSession(c.Request.Context(), claims.UserID, c.Param("id"), claims.UserID, c.ClientIP())
+	if err != nil {
+		if errors.Is(err, service.ErrSessionNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to revoke session"})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
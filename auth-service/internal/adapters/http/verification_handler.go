@@ -0,0 +1,56 @@
+package http
+
+import (
+	"auth-service/internal/service"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type VerificationHandler struct {
+	service *service.VerificationService
+}
+
+func NewVerificationHandler(s *service.VerificationService) *VerificationHandler {
+	return &VerificationHandler{service: s}
+}
+
+func (h *VerificationHandler) Verify(c *gin.Context) {
+	var req struct {
+		Token string `json:"token" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.service.Verify(c.Request.Context(), req.Token); err != nil {
+		if errors.Is(err, service.ErrInvalidVerificationToken) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to verify email"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "email verified"})
+}
+
+func (h *VerificationHandler) Resend(c *gin.Context) {
+	var req struct {
+		Email string `json:"email" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Immer 202, damit sich Konten und ihr Status nicht abfragen lassen
+	if err := h.service.Resend(c.Request.Context(), req.Email); err != nil {
+		log.Printf("Resending verification mail failed: %v", err)
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"message": "if the account exists, a verification link has been sent"})
+}
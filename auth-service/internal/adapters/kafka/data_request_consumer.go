@@ -0,0 +1,57 @@
+package kafka
+
+import (
+	"auth-service/internal/domain"
+	"context"
+	"encoding/json"
+	"log"
+
+	"github.com/segmentio/kafka-go"
+)
+
+const dataRequestEventsTopic = "data-request-events"
+
+// DataRequestResultHandler nimmt die Rückmeldungen der anderen Services an.
+type DataRequestResultHandler interface {
+	HandleResult(ctx context.Context, result *domain.DataRequestResult) error
+}
+
+// DataRequestConsumer liest die Ergebnisse von Export- und Löschanfragen.
+type DataRequestConsumer struct {
+	reader *kafka.Reader
+}
+
+func NewDataRequestConsumer(broker string) *DataRequestConsumer {
+	return &DataRequestConsumer{
+		reader: kafka.NewReader(kafka.ReaderConfig{
+			Brokers: []string{broker},
+			Topic:   dataRequestEventsTopic,
+			GroupID: "auth-service-data-requests",
+		}),
+	}
+}
+
+func (c *DataRequestConsumer) StartConsuming(ctx context.Context, handler DataRequestResultHandler) error {
+	log.Printf("Starting to consume from '%s' topic", dataRequestEventsTopic)
+	defer c.reader.Close()
+
+	for {
+		message, err := c.reader.ReadMessage(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			log.Printf("Failed to read data request result: %v", err)
+			continue
+		}
+
+		var result domain.DataRequestResult
+		if err := json.Unmarshal(message.Value, &result); err != nil {
+			log.Printf("Failed to decode data request result: %v", err)
+			continue
+		}
+		if err := handler.HandleResult(ctx, &result); err != nil {
+			log.Printf("Failed to handle %s result for data request %s: %v", result.Service, result.RequestID, err)
+		}
+	}
+}
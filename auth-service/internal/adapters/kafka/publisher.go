@@ -0,0 +1,47 @@
+package kafka
+
+import (
+	"auth-service/internal/domain"
+	"auth-service/internal/ports"
+	"context"
+	"encoding/json"
+	"log"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+type EventPublisher struct {
+	writer *kafka.Writer
+}
+
+func NewEventPublisher(broker string) ports.EventPublisher {
+	return &EventPublisher{
+		writer: &kafka.Writer{
+			Addr:         kafka.TCP(broker),
+			Topic:        "auth-events",
+			Balancer:     &kafka.LeastBytes{},
+			BatchTimeout: 10 * time.Millisecond,
+		},
+	}
+}
+
+func (p *EventPublisher) Publish(ctx context.Context, event *domain.AuthEvent) error {
+	eventData, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	message := kafka.Message{
+		Key:   []byte(event.UserID),
+		Value: eventData,
+	}
+
+	if err := p.writer.WriteMessages(ctx, message); err != nil {
+		log.Printf("Failed to publish %s event: %v", event.EventType, err)
+		return err
+	}
+
+	log.Printf("Published %s event for user %s", event.EventType, event.UserID)
+	return nil
+}
@@ -0,0 +1,41 @@
+package mail
+
+import (
+	"auth-service/internal/domain"
+	"auth-service/internal/ports"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// FileMailer verschickt keine echten Mails, sondern legt jede Nachricht als
+// .eml-Datei im Outbox-Verzeichnis ab und schreibt sie ins Log. Damit lassen
+// sich Reset- und Verifizierungs-Flows lokal ohne Mailserver testen.
+type FileMailer struct {
+	dir  string
+	from string
+}
+
+func NewFileMailer(dir, from string) (ports.Mailer, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &FileMailer{dir: dir, from: from}, nil
+}
+
+func (m *FileMailer) Send(mail *domain.Mail) error {
+	now := time.Now()
+	msg := fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: %s\r\nDate: %s\r\nContent-Type: text/plain; charset=utf-8\r\n\r\n%s\r\n",
+		m.from, mail.To, mail.Subject, now.Format(time.RFC1123Z), mail.Body)
+
+	name := fmt.Sprintf("%d-%s.eml", now.UnixNano(), strings.NewReplacer("/", "_", "\\", "_").Replace(mail.To))
+	if err := os.WriteFile(filepath.Join(m.dir, name), []byte(msg), 0o644); err != nil {
+		return err
+	}
+
+	log.Printf("MAIL to=%s subject=%q file=%s\n%s", mail.To, mail.Subject, name, mail.Body)
+	return nil
+}
@@ -0,0 +1,93 @@
+package mongo
+
+import (
+	"auth-service/internal/domain"
+	"auth-service/internal/ports"
+	"context"
+	"log"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type AuditRepository struct {
+	collection *mongo.Collection
+}
+
+func NewAuditRepository(db *mongo.Database) ports.AuditRepository {
+	r := &AuditRepository{
+		collection: db.Collection("audit_log"),
+	}
+	// Typische Abfragen: alles zu einem User bzw. alle Events eines Typs,
+	// jeweils zeitlich absteigend.
+	_, err := r.collection.Indexes().CreateMany(context.Background(), []mongo.IndexModel{
+		{Keys: bson.D{{Key: "timestamp", Value: -1}}},
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "timestamp", Value: -1}}},
+		{Keys: bson.D{{Key: "event_type", Value: 1}, {Key: "timestamp", Value: -1}}},
+	})
+	if err != nil {
+		log.Printf("Failed to create indexes on audit_log: %v", err)
+	}
+	return r
+}
+
+func (r *AuditRepository) Append(ctx context.Context, event *domain.AuthEvent) error {
+	_, err := r.collection.InsertOne(ctx, event)
+	return err
+}
+
+func (r *AuditRepository) Find(ctx context.Context, filter domain.AuditFilter) ([]domain.AuthEvent, int64, error) {
+	query := bson.M{}
+	if filter.EventType != "" {
+		query["event_type"] = filter.EventType
+	}
+	if filter.UserID != "" {
+		query["user_id"] = filter.UserID
+	}
+	if filter.ActorID != "" {
+		query["actor_id"] = filter.ActorID
+	}
+	if filter.Email != "" {
+		query["email"] = filter.Email
+	}
+	if filter.IP != "" {
+		query["ip"] = filter.IP
+	}
+	timeRange := bson.M{}
+	if filter.From != nil {
+		timeRange["$gte"] = *filter.From
+	}
+	if filter.To != nil {
+		timeRange["$lt"] = *filter.To
+	}
+	if len(timeRange) > 0 {
+		query["timestamp"] = timeRange
+	}
+
+	total, err := r.collection.CountDocuments(ctx, query)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "timestamp", Value: -1}, {Key: "_id", Value: -1}}).
+		SetSkip(int64((filter.Page - 1) * filter.PageSize)).
+		SetLimit(int64(filter.PageSize))
+	cursor, err := r.collection.Find(ctx, query, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	events := []domain.AuthEvent{}
+	if err := cursor.All(ctx, &events); err != nil {
+		return nil, 0, err
+	}
+	return events, total, nil
+}
+
+func (r *AuditRepository) AnonymizeUser(ctx context.Context, userID string) error {
+	_, err := r.collection.UpdateMany(ctx, bson.M{"user_id": userID}, bson.M{"$unset": bson.M{"email": "", "ip": ""}})
+	return err
+}
@@ -0,0 +1,79 @@
+package mongo
+
+import (
+	"auth-service/internal/domain"
+	"auth-service/internal/ports"
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type ClientRepository struct {
+	collection *mongo.Collection
+}
+
+func NewClientRepository(db *mongo.Database) ports.ClientRepository {
+	return &ClientRepository{
+		collection: db.Collection("clients"),
+	}
+}
+
+func (r *ClientRepository) Save(client *domain.Client) error {
+	_, err := r.collection.ReplaceOne(context.Background(),
+		bson.M{"_id": client.ID}, client, options.Replace().SetUpsert(true))
+	return err
+}
+
+func (r *ClientRepository) CreateIfMissing(client *domain.Client) (bool, error) {
+	result, err := r.collection.UpdateOne(context.Background(),
+		bson.M{"_id": client.ID}, bson.M{"$setOnInsert": client}, options.Update().SetUpsert(true))
+	if err != nil {
+		return false, err
+	}
+	return result.UpsertedCount > 0, nil
+}
+
+func (r *ClientRepository) FindByID(id string) (*domain.Client, error) {
+	var client domain.Client
+	if err := r.collection.FindOne(context.Background(), bson.M{"_id": id}).Decode(&client); err != nil {
+		return nil, err
+	}
+	return &client, nil
+}
+
+func (r *ClientRepository) FindAll() ([]domain.Client, error) {
+	ctx := context.Background()
+	cursor, err := r.collection.Find(ctx, bson.M{}, options.Find().SetSort(bson.M{"created_at": 1}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	clients := []domain.Client{}
+	if err := cursor.All(ctx, &clients); err != nil {
+		return nil, err
+	}
+	return clients, nil
+}
+
+func (r *ClientRepository) UpdateSecret(id, secretHash string, rotatedAt time.Time) error {
+	return r.update(id, bson.M{"$set": bson.M{"secret_hash": secretHash, "rotated_at": rotatedAt}})
+}
+
+func (r *ClientRepository) Revoke(id string) error {
+	return r.update(id, bson.M{"$set": bson.M{"revoked": true}})
+}
+
+func (r *ClientRepository) update(id string, update bson.M) error {
+	res, err := r.collection.UpdateOne(context.Background(), bson.M{"_id": id}, update)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
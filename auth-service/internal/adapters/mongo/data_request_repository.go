@@ -0,0 +1,91 @@
+package mongo
+
+import (
+	"auth-service/internal/domain"
+	"auth-service/internal/ports"
+	"context"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type DataRequestRepository struct {
+	requests *mongo.Collection
+	parts    *mongo.Collection
+}
+
+func NewDataRequestRepository(db *mongo.Database) ports.DataRequestRepository {
+	r := &DataRequestRepository{
+		requests: db.Collection("data_requests"),
+		parts:    db.Collection("data_export_parts"),
+	}
+	// Anfragen und Exportdaten entfernt MongoDB nach Ablauf selbst
+	ttl := mongo.IndexModel{Keys: bson.M{"expires_at": 1}, Options: options.Index().SetExpireAfterSeconds(0)}
+	if _, err := r.requests.Indexes().CreateOne(context.Background(), ttl); err != nil {
+		log.Printf("Failed to create TTL index on data_requests: %v", err)
+	}
+	_, err := r.parts.Indexes().CreateMany(context.Background(), []mongo.IndexModel{
+		ttl,
+		{Keys: bson.D{{Key: "request_id", Value: 1}, {Key: "service", Value: 1}}, Options: options.Index().SetUnique(true)},
+	})
+	if err != nil {
+		log.Printf("Failed to create indexes on data_export_parts: %v", err)
+	}
+	return r
+}
+
+func (r *DataRequestRepository) Create(ctx context.Context, request *domain.DataRequest) error {
+	_, err := r.requests.InsertOne(ctx, request)
+	return err
+}
+
+func (r *DataRequestRepository) FindByID(ctx context.Context, id string) (*domain.DataRequest, error) {
+	var request domain.DataRequest
+	if err := r.requests.FindOne(ctx, bson.M{"_id": id}).Decode(&request); err != nil {
+		return nil, err
+	}
+	return &request, nil
+}
+
+func (r *DataRequestRepository) SetServiceStatus(ctx context.Context, id, service string, status domain.DataRequestServiceStatus) (*domain.DataRequest, error) {
+	var request domain.DataRequest
+	err := r.requests.FindOneAndUpdate(ctx,
+		bson.M{"_id": id},
+		bson.M{"$set": bson.M{"services." + service: status}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&request)
+	if err != nil {
+		return nil, err
+	}
+	return &request, nil
+}
+
+func (r *DataRequestRepository) Finish(ctx context.Context, id, status string) error {
+	_, err := r.requests.UpdateOne(ctx,
+		bson.M{"_id": id, "status": domain.DataRequestPending},
+		bson.M{"$set": bson.M{"status": status, "completed_at": time.Now()}})
+	return err
+}
+
+func (r *DataRequestRepository) SaveExportPart(ctx context.Context, part *domain.DataExportPart) error {
+	_, err := r.parts.ReplaceOne(ctx,
+		bson.M{"request_id": part.RequestID, "service": part.Service}, part, options.Replace().SetUpsert(true))
+	return err
+}
+
+func (r *DataRequestRepository) FindExportParts(ctx context.Context, requestID string) ([]domain.DataExportPart, error) {
+	cursor, err := r.parts.Find(ctx, bson.M{"request_id": requestID}, options.Find().SetSort(bson.M{"service": 1}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	parts := []domain.DataExportPart{}
+	if err := cursor.All(ctx, &parts); err != nil {
+		return nil, err
+	}
+	return parts, nil
+}
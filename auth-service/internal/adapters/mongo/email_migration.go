@@ -0,0 +1,93 @@
+package mongo
+
+import (
+	"auth-service/internal/domain"
+	"context"
+	"fmt"
+	"sort"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// EmailDuplicate sind Konten, deren Adressen sich nur in Groß-/Kleinschreibung
+// oder Leerzeichen unterscheiden.
+type EmailDuplicate struct {
+	Email string
+	Users []domain.User
+}
+
+type EmailMigrationReport struct {
+	// Normalized zählt die Adressen, die umgeschrieben wurden (bzw. bei
+	// dryRun umgeschrieben würden).
+	Normalized int
+	Duplicates []EmailDuplicate
+}
+
+// NormalizeEmails schreibt alle gespeicherten Adressen in die normalisierte
+// Form um. Duplikate werden nicht angefasst, sondern nur gemeldet; sie müssen
+// von Hand zusammengeführt oder gelöscht werden.
+func NormalizeEmails(ctx context.Context, db *mongo.Database, collectionName string, dryRun bool) (*EmailMigrationReport, error) {
+	collection := db.Collection(collectionName)
+	opts := options.Find().
+		SetProjection(bson.M{"email": 1, "role": 1, "verified": 1, "created_at": 1}).
+		SetSort(bson.M{"_id": 1})
+	cursor, err := collection.Find(ctx, bson.M{}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	byEmail := map[string][]domain.User{}
+	for cursor.Next(ctx) {
+		var user domain.User
+		if err := cursor.Decode(&user); err != nil {
+			return nil, err
+		}
+		fillCreatedAt(&user)
+		email := domain.NormalizeEmail(user.Email)
+		byEmail[email] = append(byEmail[email], user)
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, err
+	}
+
+	repo := &UserRepository{collection: collection}
+	report := &EmailMigrationReport{}
+	for email, users := range byEmail {
+		if len(users) > 1 {
+			report.Duplicates = append(report.Duplicates, EmailDuplicate{Email: email, Users: users})
+			continue
+		}
+		if users[0].Email == email {
+			continue
+		}
+		report.Normalized++
+		if dryRun {
+			continue
+		}
+		if err := repo.updateByID(ctx, users[0].ID, bson.M{"$set": bson.M{"email": email}}); err != nil {
+			return nil, err
+		}
+	}
+	sort.Slice(report.Duplicates, func(i, j int) bool { return report.Duplicates[i].Email < report.Duplicates[j].Email })
+	return report, nil
+}
+
+// MigrateEmails normalisiert die Adressen und legt danach den eindeutigen
+// Index an. Logins suchen nur noch nach der normalisierten Form, daher läuft
+// das bei jedem Start; gibt es Duplikate, darf der Service nicht starten.
+func MigrateEmails(ctx context.Context, db *mongo.Database, collectionName string) (*EmailMigrationReport, error) {
+	report, err := NormalizeEmails(ctx, db, collectionName, false)
+	if err != nil {
+		return nil, err
+	}
+	if len(report.Duplicates) > 0 {
+		return report, fmt.Errorf("found %d duplicate email addresses, run 'auth-service migrate -dry-run' for details", len(report.Duplicates))
+	}
+	if err := EnsureEmailIndex(ctx, db, collectionName); err != nil {
+		return report, fmt.Errorf("create unique email index: %w", err)
+	}
+	return report, nil
+}
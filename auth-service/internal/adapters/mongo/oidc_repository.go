@@ -0,0 +1,78 @@
+package mongo
+
+import (
+	"auth-service/internal/domain"
+	"auth-service/internal/ports"
+	"context"
+	"log"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type ConsentRepository struct {
+	collection *mongo.Collection
+}
+
+func NewConsentRepository(db *mongo.Database) ports.ConsentRepository {
+	r := &ConsentRepository{
+		collection: db.Collection("oidc_consents"),
+	}
+	_, err := r.collection.Indexes().CreateOne(context.Background(), mongo.IndexModel{
+		Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "client_id", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		log.Printf("Failed to create index on oidc_consents: %v", err)
+	}
+	return r
+}
+
+func (r *ConsentRepository) Find(ctx context.Context, userID, clientID string) (*domain.Consent, error) {
+	var consent domain.Consent
+	err := r.collection.FindOne(ctx, bson.M{"user_id": userID, "client_id": clientID}).Decode(&consent)
+	if err != nil {
+		return nil, err
+	}
+	return &consent, nil
+}
+
+func (r *ConsentRepository) Save(ctx context.Context, consent *domain.Consent) error {
+	_, err := r.collection.ReplaceOne(ctx,
+		bson.M{"user_id": consent.UserID, "client_id": consent.ClientID}, consent, options.Replace().SetUpsert(true))
+	return err
+}
+
+type AuthorizationCodeRepository struct {
+	collection *mongo.Collection
+}
+
+func NewAuthorizationCodeRepository(db *mongo.Database) ports.AuthorizationCodeRepository {
+	r := &AuthorizationCodeRepository{
+		collection: db.Collection("oidc_authorization_codes"),
+	}
+	// Nicht eingelöste Codes entfernt MongoDB nach Ablauf selbst
+	_, err := r.collection.Indexes().CreateOne(context.Background(), mongo.IndexModel{
+		Keys:    bson.M{"expires_at": 1},
+		Options: options.Index().SetExpireAfterSeconds(0),
+	})
+	if err != nil {
+		log.Printf("Failed to create TTL index on oidc_authorization_codes: %v", err)
+	}
+	return r
+}
+
+func (r *AuthorizationCodeRepository) Create(ctx context.Context, code *domain.AuthorizationCode) error {
+	_, err := r.collection.InsertOne(ctx, code)
+	return err
+}
+
+func (r *AuthorizationCodeRepository) Consume(ctx context.Context, codeHash string) (*domain.AuthorizationCode, error) {
+	var code domain.AuthorizationCode
+	err := r.collection.FindOneAndDelete(ctx, bson.M{"_id": codeHash}).Decode(&code)
+	if err != nil {
+		return nil, err
+	}
+	return &code, nil
+}
@@ -0,0 +1,62 @@
+package mongo
+
+import (
+	"auth-service/internal/domain"
+	"auth-service/internal/ports"
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type OneTimeTokenRepository struct {
+	collection *mongo.Collection
+}
+
+func NewOneTimeTokenRepository(db *mongo.Database) ports.OneTimeTokenRepository {
+	return &OneTimeTokenRepository{
+		collection: db.Collection("one_time_tokens"),
+	}
+}
+
+func (r *OneTimeTokenRepository) Create(token *domain.OneTimeToken) error {
+	_, err := r.collection.InsertOne(context.Background(), token)
+	return err
+}
+
+func (r *OneTimeTokenRepository) Consume(hash, purpose string) (*domain.OneTimeToken, error) {
+	now := time.Now()
+	filter := bson.M{
+		"token_hash": hash,
+		"purpose":    purpose,
+		"used_at":    bson.M{"$exists": false},
+		"expires_at": bson.M{"$gt": now},
+	}
+	update := bson.M{"$set": bson.M{"used_at": now}}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var token domain.OneTimeToken
+	err := r.collection.FindOneAndUpdate(context.Background(), filter, update, opts).Decode(&token)
+	if err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+func (r *OneTimeTokenRepository) InvalidateForUser(userID, purpose string) error {
+	filter := bson.M{"user_id": userID, "purpose": purpose, "used_at": bson.M{"$exists": false}}
+	_, err := r.collection.UpdateMany(context.Background(), filter, bson.M{"$set": bson.M{"used_at": time.Now()}})
+	return err
+}
+
+func (r *OneTimeTokenRepository) LatestForUser(userID, purpose string) (*domain.OneTimeToken, error) {
+	opts := options.FindOne().SetSort(bson.M{"created_at": -1})
+	var token domain.OneTimeToken
+	err := r.collection.FindOne(context.Background(), bson.M{"user_id": userID, "purpose": purpose}, opts).Decode(&token)
+	if err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
@@ -0,0 +1,59 @@
+package mongo
+
+import (
+	"auth-service/internal/domain"
+	"auth-service/internal/ports"
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type RefreshTokenRepository struct {
+	collection *mongo.Collection
+}
+
+func NewRefreshTokenRepository(db *mongo.Database) ports.RefreshTokenRepository {
+	return &RefreshTokenRepository{
+		collection: db.Collection("refresh_tokens"),
+	}
+}
+
+func (r *RefreshTokenRepository) Create(token *domain.RefreshToken) error {
+	_, err := r.collection.InsertOne(context.Background(), token)
+	return err
+}
+
+func (r *RefreshTokenRepository) FindByHash(hash string) (*domain.RefreshToken, error) {
+	var token domain.RefreshToken
+	err := r.collection.FindOne(context.Background(), bson.M{"token_hash": hash}).Decode(&token)
+	if err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+func (r *RefreshTokenRepository) MarkUsed(id, replacedBy string) (bool, error) {
+	// Bedingtes Update, damit zwei parallele Refreshs mit demselben Token
+	// nicht beide erfolgreich rotieren können.
+	filter := bson.M{"_id": id, "used_at": bson.M{"$exists": false}, "revoked": false}
+	update := bson.M{"$set": bson.M{"used_at": time.Now(), "replaced_by": replacedBy}}
+	res, err := r.collection.UpdateOne(context.Background(), filter, update)
+	if err != nil {
+		return false, err
+	}
+	return res.ModifiedCount == 1, nil
+}
+
+func (r *RefreshTokenRepository) RevokeFamily(familyID string) error {
+	_, err := r.collection.UpdateMany(context.Background(),
+		bson.M{"family_id": familyID}, bson.M{"$set": bson.M{"revoked": true}})
+	return err
+}
+
+func (r *RefreshTokenRepository) RevokeAllForUser(userID string) error {
+	_, err := r.collection.UpdateMany(context.Background(),
+		bson.M{"user_id": userID}, bson.M{"$set": bson.M{"revoked": true}})
+	return err
+}
@@ -0,0 +1,66 @@
+package mongo
+
+import (
+	"auth-service/internal/domain"
+	"auth-service/internal/ports"
+	"context"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type RevocationRepository struct {
+	tokens *mongo.Collection
+	users  *mongo.Collection
+}
+
+func NewRevocationRepository(db *mongo.Database) ports.RevocationRepository {
+	r := &RevocationRepository{
+		tokens: db.Collection("revoked_tokens"),
+		users:  db.Collection("user_token_revocations"),
+	}
+	// Einträge werden nach Ablauf der gesperrten Tokens von MongoDB entfernt
+	for _, coll := range []*mongo.Collection{r.tokens, r.users} {
+		_, err := coll.Indexes().CreateOne(context.Background(), mongo.IndexModel{
+			Keys:    bson.M{"expires_at": 1},
+			Options: options.Index().SetExpireAfterSeconds(0),
+		})
+		if err != nil {
+			log.Printf("Failed to create TTL index on %s: %v", coll.Name(), err)
+		}
+	}
+	return r
+}
+
+func (r *RevocationRepository) RevokeToken(token *domain.RevokedToken) error {
+	_, err := r.tokens.ReplaceOne(context.Background(),
+		bson.M{"_id": token.JTI}, token, options.Replace().SetUpsert(true))
+	return err
+}
+
+func (r *RevocationRepository) RevokeUserTokens(revocation *domain.UserTokenRevocation) error {
+	_, err := r.users.ReplaceOne(context.Background(),
+		bson.M{"_id": revocation.UserID}, revocation, options.Replace().SetUpsert(true))
+	return err
+}
+
+func (r *RevocationRepository) IsRevoked(jti, userID string, issuedAt time.Time) (bool, error) {
+	if jti != "" {
+		count, err := r.tokens.CountDocuments(context.Background(), bson.M{"_id": jti})
+		if err != nil {
+			return false, err
+		}
+		if count > 0 {
+			return true, nil
+		}
+	}
+	count, err := r.users.CountDocuments(context.Background(),
+		bson.M{"_id": userID, "revoked_before": bson.M{"$gt": issuedAt}})
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
@@ -0,0 +1,42 @@
+package mongo
+
+import (
+	"auth-service/internal/domain"
+	"auth-service/internal/ports"
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type RoleChangeRepository struct {
+	collection *mongo.Collection
+}
+
+func NewRoleChangeRepository(db *mongo.Database) ports.RoleChangeRepository {
+	return &RoleChangeRepository{
+		collection: db.Collection("role_changes"),
+	}
+}
+
+func (r *RoleChangeRepository) Create(change *domain.RoleChange) error {
+	_, err := r.collection.InsertOne(context.Background(), change)
+	return err
+}
+
+func (r *RoleChangeRepository) FindByUser(userID string) ([]domain.RoleChange, error) {
+	ctx := context.Background()
+	opts := options.Find().SetSort(bson.M{"changed_at": -1})
+	cursor, err := r.collection.Find(ctx, bson.M{"user_id": userID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	changes := []domain.RoleChange{}
+	if err := cursor.All(ctx, &changes); err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
@@ -0,0 +1,88 @@
+package mongo
+
+import (
+	"auth-service/internal/domain"
+	"auth-service/internal/ports"
+	"context"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type SessionRepository struct {
+	collection *mongo.Collection
+}
+
+func NewSessionRepository(db *mongo.Database) ports.SessionRepository {
+	r := &SessionRepository{
+		collection: db.Collection("sessions"),
+	}
+	_, err := r.collection.Indexes().CreateMany(context.Background(), []mongo.IndexModel{
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "last_seen_at", Value: -1}}},
+		// Abgelaufene Sitzungen entfernt MongoDB selbst
+		{Keys: bson.M{"expires_at": 1}, Options: options.Index().SetExpireAfterSeconds(0)},
+	})
+	if err != nil {
+		log.Printf("Failed to create indexes on sessions: %v", err)
+	}
+	return r
+}
+
+func (r *SessionRepository) Save(ctx context.Context, session *domain.Session) error {
+	_, err := r.collection.UpdateOne(ctx, bson.M{"_id": session.ID}, bson.M{
+		"$set": bson.M{
+			"user_id":           session.UserID,
+			"user_agent":        session.UserAgent,
+			"ip":                session.IP,
+			"last_seen_at":      session.LastSeenAt,
+			"expires_at":        session.ExpiresAt,
+			"access_jti":        session.AccessJTI,
+			"access_expires_at": session.AccessExpiresAt,
+		},
+		"$setOnInsert": bson.M{"created_at": session.CreatedAt},
+	}, options.Update().SetUpsert(true))
+	return err
+}
+
+func (r *SessionRepository) FindByID(ctx context.Context, id string) (*domain.Session, error) {
+	var session domain.Session
+	err := r.collection.FindOne(ctx, bson.M{"_id": id, "expires_at": bson.M{"$gt": time.Now()}}).Decode(&session)
+	if err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+func (r *SessionRepository) FindByUser(ctx context.Context, userID string) ([]domain.Session, error) {
+	opts := options.Find().SetSort(bson.M{"last_seen_at": -1})
+	cursor, err := r.collection.Find(ctx, bson.M{"user_id": userID, "expires_at": bson.M{"$gt": time.Now()}}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	sessions := []domain.Session{}
+	if err := cursor.All(ctx, &sessions); err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+func (r *SessionRepository) Delete(ctx context.Context, id string) error {
+	res, err := r.collection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+	if res.DeletedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+func (r *SessionRepository) DeleteAllForUser(ctx context.Context, userID string) error {
+	_, err := r.collection.DeleteMany(ctx, bson.M{"user_id": userID})
+	return err
+}
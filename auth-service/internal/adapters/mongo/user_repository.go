@@ -4,31 +4,403 @@ import (
 	"auth-service/internal/domain"
 	"auth-service/internal/ports"
 	"context"
+	"errors"
+	"log"
+	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type UserRepository struct {
 	collection *mongo.Collection
 }
 
+// NewUserRepository setzt voraus, dass die Adressen normalisiert sind und der
+// eindeutige Index existiert (siehe MigrateEmails).
 func NewUserRepository(db *mongo.Database, collectionName string) ports.UserRepository {
 	return &UserRepository{
 		collection: db.Collection(collectionName),
 	}
 }
 
-func (r *UserRepository) Create(user *domain.User) error {
-	_, err := r.collection.InsertOne(context.Background(), user)
+// EnsureEmailIndex legt den eindeutigen Index auf email an. Schlägt fehl,
+// solange es Konten mit derselben Adresse gibt.
+func EnsureEmailIndex(ctx context.Context, db *mongo.Database, collectionName string) error {
+	_, err := db.Collection(collectionName).Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.M{"email": 1},
+		Options: options.Index().SetUnique(true).SetName("email_unique"),
+	})
 	return err
 }
 
-func (r *UserRepository) FindByEmail(email string) (*domain.User, error) {
+func (r *UserRepository) Create(ctx context.Context, user *domain.User) error {
+	if user.CreatedAt.IsZero() {
+		user.CreatedAt = time.Now()
+	}
+	user.Email = domain.NormalizeEmail(user.Email)
+	res, err := r.collection.InsertOne(ctx, user)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return ports.ErrUserExists
+		}
+		return err
+	}
+	if oid, ok := res.InsertedID.(primitive.ObjectID); ok {
+		user.ID = oid.Hex()
+	}
+	return nil
+}
+
+func (r *UserRepository) FindByEmail(ctx context.Context, email string) (*domain.User, error) {
+	var user domain.User
+	err := r.collection.FindOne(ctx, bson.M{"email": domain.NormalizeEmail(email)}).Decode(&user)
+	if err != nil {
+		return nil, err
+	}
+	fillCreatedAt(&user)
+	return &user, nil
+}
+
+func (r *UserRepository) FindByID(ctx context.Context, id string) (*domain.User, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
 	var user domain.User
-	err := r.collection.FindOne(context.Background(), bson.M{"email": email}).Decode(&user)
+	err = r.collection.FindOne(ctx, bson.M{"_id": objectID}).Decode(&user)
 	if err != nil {
 		return nil, err
 	}
+	fillCreatedAt(&user)
 	return &user, nil
 }
+
+func (r *UserRepository) UpdateRole(ctx context.Context, id, role string) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+	res, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": objectID}, bson.M{"$set": bson.M{"role": role}})
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+func (r *UserRepository) UpdatePassword(ctx context.Context, id, passwordHash string) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+	res, err := r.collection.UpdateOne(ctx, bson.M{"_id": objectID}, bson.M{
+		"$set":   bson.M{"password": passwordHash},
+		"$unset": bson.M{"password_reset_required": ""},
+	})
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+func (r *UserRepository) ReplacePasswordHash(ctx context.Context, id, oldHash, newHash string) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+	_, err = r.collection.UpdateOne(ctx, bson.M{"_id": objectID, "password": oldHash}, bson.M{
+		"$set": bson.M{"password": newHash},
+	})
+	return err
+}
+
+func (r *UserRepository) MarkVerified(ctx context.Context, id string) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+	_, err = r.collection.UpdateOne(ctx,
+		bson.M{"_id": objectID}, bson.M{"$set": bson.M{"verified": true}})
+	return err
+}
+
+// MarkLegacyUsersVerified setzt verified=true für alle Konten, die vor
+// Einführung der E-Mail-Verifizierung angelegt wurden und das Feld noch nicht
+// haben. Neue Konten werden immer mit verified=false gespeichert.
+func MarkLegacyUsersVerified(db *mongo.Database, collectionName string) error {
+	res, err := db.Collection(collectionName).UpdateMany(context.Background(),
+		bson.M{"verified": bson.M{"$exists": false}}, bson.M{"$set": bson.M{"verified": true}})
+	if err != nil {
+		return err
+	}
+	if res.ModifiedCount > 0 {
+		log.Printf("Marked %d legacy users as verified", res.ModifiedCount)
+	}
+	return nil
+}
+
+func (r *UserRepository) BeginLoginAttempt(ctx context.Context, id string, seen int) (int, bool, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return 0, false, err
+	}
+	filter := bson.M{"_id": objectID, "failed_logins": seen}
+	if seen == 0 {
+		filter = bson.M{
+			"_id": objectID,
+			"$or": bson.A{
+				bson.M{"failed_logins": bson.M{"$exists": false}},
+				bson.M{"failed_logins": 0},
+			},
+		}
+	}
+	update := bson.M{
+		"$inc": bson.M{"failed_logins": 1},
+		"$set": bson.M{"last_failed_login": time.Now()},
+	}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var user domain.User
+	err = r.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&user)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	return user.FailedLogins, true, nil
+}
+
+func (r *UserRepository) LockUntil(ctx context.Context, id string, until time.Time) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+	_, err = r.collection.UpdateOne(ctx,
+		bson.M{"_id": objectID}, bson.M{"$set": bson.M{"locked_until": until}})
+	return err
+}
+
+func (r *UserRepository) ResetFailedLogins(ctx context.Context, id string) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+	update := bson.M{
+		"$set":   bson.M{"failed_logins": 0},
+		"$unset": bson.M{"last_failed_login": "", "locked_until": ""},
+	}
+	res, err := r.collection.UpdateOne(ctx, bson.M{"_id": objectID}, update)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+func (r *UserRepository) SetPendingTOTPSecret(ctx context.Context, id, secret string) error {
+	return r.updateByID(ctx, id, bson.M{"$set": bson.M{"pending_totp_secret": secret}})
+}
+
+func (r *UserRepository) EnableTOTP(ctx context.Context, id, secret string, recoveryCodeHashes []string) error {
+	return r.updateByID(ctx, id, bson.M{
+		"$set": bson.M{
+			"mfa_enabled":    true,
+			"totp_secret":    secret,
+			"recovery_codes": recoveryCodeHashes,
+		},
+		"$unset": bson.M{"pending_totp_secret": "", "last_totp_counter": ""},
+	})
+}
+
+func (r *UserRepository) DisableTOTP(ctx context.Context, id string) error {
+	return r.updateByID(ctx, id, bson.M{
+		"$set":   bson.M{"mfa_enabled": false},
+		"$unset": bson.M{"totp_secret": "", "pending_totp_secret": "", "recovery_codes": "", "last_totp_counter": ""},
+	})
+}
+
+func (r *UserRepository) AdvanceTOTPCounter(ctx context.Context, id string, counter int64) (bool, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return false, err
+	}
+	filter := bson.M{
+		"_id": objectID,
+		"$or": bson.A{
+			bson.M{"last_totp_counter": bson.M{"$exists": false}},
+			bson.M{"last_totp_counter": bson.M{"$lt": counter}},
+		},
+	}
+	res, err := r.collection.UpdateOne(ctx, filter, bson.M{"$set": bson.M{"last_totp_counter": counter}})
+	if err != nil {
+		return false, err
+	}
+	return res.ModifiedCount == 1, nil
+}
+
+func (r *UserRepository) UseRecoveryCode(ctx context.Context, id, codeHash string) (bool, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return false, err
+	}
+	res, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": objectID, "recovery_codes": codeHash},
+		bson.M{"$pull": bson.M{"recovery_codes": codeHash}})
+	if err != nil {
+		return false, err
+	}
+	return res.ModifiedCount == 1, nil
+}
+
+func (r *UserRepository) List(ctx context.Context, filter domain.UserFilter) ([]domain.User, int64, error) {
+	query := bson.M{}
+	if filter.Role != "" {
+		query["role"] = filter.Role
+	}
+
+	// Das Anlagedatum steckt in der ObjectID, so funktioniert der Filter auch
+	// für Konten ohne created_at.
+	idRange := bson.M{}
+	if filter.CreatedAfter != nil {
+		idRange["$gte"] = primitive.NewObjectIDFromTimestamp(*filter.CreatedAfter)
+	}
+	if filter.CreatedBefore != nil {
+		idRange["$lt"] = primitive.NewObjectIDFromTimestamp(*filter.CreatedBefore)
+	}
+	if len(idRange) > 0 {
+		query["_id"] = idRange
+	}
+
+	now := time.Now()
+	notLocked := bson.A{
+		bson.M{"locked_until": bson.M{"$exists": false}},
+		bson.M{"locked_until": bson.M{"$lte": now}},
+	}
+	switch filter.Status {
+	case domain.UserStatusDisabled:
+		query["disabled"] = true
+	case domain.UserStatusLocked:
+		query["disabled"] = bson.M{"$ne": true}
+		query["locked_until"] = bson.M{"$gt": now}
+	case domain.UserStatusUnverified:
+		query["disabled"] = bson.M{"$ne": true}
+		query["verified"] = false
+		query["$or"] = notLocked
+	case domain.UserStatusActive:
+		query["disabled"] = bson.M{"$ne": true}
+		query["verified"] = true
+		query["$or"] = notLocked
+	}
+
+	total, err := r.collection.CountDocuments(ctx, query)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	opts := options.Find().
+		SetSort(bson.M{"_id": -1}).
+		SetSkip(int64((filter.Page - 1) * filter.PageSize)).
+		SetLimit(int64(filter.PageSize))
+	cursor, err := r.collection.Find(ctx, query, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	users := []domain.User{}
+	if err := cursor.All(ctx, &users); err != nil {
+		return nil, 0, err
+	}
+	for i := range users {
+		fillCreatedAt(&users[i])
+	}
+	return users, total, nil
+}
+
+func (r *UserRepository) SetDisabled(ctx context.Context, id string, disabled bool) error {
+	if disabled {
+		return r.updateByID(ctx, id, bson.M{"$set": bson.M{"disabled": true, "disabled_at": time.Now()}})
+	}
+	return r.updateByID(ctx, id, bson.M{"$unset": bson.M{"disabled": "", "disabled_at": ""}})
+}
+
+func (r *UserRepository) RequirePasswordReset(ctx context.Context, id string) error {
+	return r.updateByID(ctx, id, bson.M{"$set": bson.M{"password_reset_required": true}})
+}
+
+func (r *UserRepository) Delete(ctx context.Context, id string) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+	res, err := r.collection.DeleteOne(ctx, bson.M{"_id": objectID})
+	if err != nil {
+		return err
+	}
+	if res.DeletedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+func (r *UserRepository) UpdateProfile(ctx context.Context, id string, update domain.ProfileUpdate) error {
+	set := bson.M{}
+	if update.DisplayName != nil {
+		set["display_name"] = *update.DisplayName
+	}
+	if update.PreferredCurrency != nil {
+		set["preferred_currency"] = *update.PreferredCurrency
+	}
+	if update.Addresses != nil {
+		set["addresses"] = *update.Addresses
+	}
+	if len(set) == 0 {
+		return nil
+	}
+	return r.updateByID(ctx, id, bson.M{"$set": set})
+}
+
+func (r *UserRepository) UpdateEmail(ctx context.Context, id, email string) error {
+	err := r.updateByID(ctx, id, bson.M{"$set": bson.M{"email": domain.NormalizeEmail(email), "verified": true}})
+	if mongo.IsDuplicateKeyError(err) {
+		return ports.ErrUserExists
+	}
+	return err
+}
+
+// fillCreatedAt ergänzt das Anlagedatum alter Konten aus der ObjectID.
+func fillCreatedAt(user *domain.User) {
+	if !user.CreatedAt.IsZero() {
+		return
+	}
+	if oid, err := primitive.ObjectIDFromHex(user.ID); err == nil {
+		user.CreatedAt = oid.Timestamp()
+	}
+}
+
+func (r *UserRepository) updateByID(ctx context.Context, id string, update bson.M) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+	res, err := r.collection.UpdateOne(ctx, bson.M{"_id": objectID}, update)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
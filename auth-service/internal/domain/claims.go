@@ -0,0 +1,39 @@
+package domain
+
+import "time"
+
+const (
+	TokenTypeAccess = "access"
+	// TokenTypeUnverified wird an unbestätigte Konten ausgegeben, wenn
+	// UNVERIFIED_LOGIN=restricted ist. Andere Services lassen damit keinen
+	// Checkout zu.
+	TokenTypeUnverified = "unverified"
+	// TokenTypeMFAPending bestätigt nur das Passwort und kann ausschließlich
+	// gegen ein echtes Token (/mfa/verify) bzw. zur Einrichtung getauscht werden.
+	TokenTypeMFAPending = "mfa_pending"
+	// TokenTypeService wird an Maschinen-Clients ausgegeben (client_credentials)
+	// und enthält statt eines Users die client_id und die Scopes.
+	TokenTypeService = "service"
+	// TokenTypeOIDC wird über den Authorization-Code-Flow an OIDC-Clients
+	// ausgegeben und gilt nur für /userinfo.
+	TokenTypeOIDC = "oidc"
+	// TokenTypeID markiert ID-Tokens, damit sie nirgends als Access-Token
+	// durchgehen.
+	TokenTypeID = "id"
+)
+
+// Claims sind die geprüften Angaben aus einem Access-Token.
+type Claims struct {
+	ID        string // jti, leer bei Tokens von vor dessen Einführung
+	SessionID string // sid, nur bei Tokens aus Login oder Refresh
+	Scope     string // nur bei OIDC-Tokens, durch Leerzeichen getrennt
+	UserID    string
+	Email     string
+	Role      string
+	// Permissions stammen aus dem Claim "permissions", bei älteren Tokens
+	// aus der Rolle.
+	Permissions []string
+	Type        string
+	IssuedAt    time.Time
+	ExpiresAt   time.Time
+}
@@ -0,0 +1,87 @@
+package domain
+
+import "time"
+
+const (
+	// ScopeIntrospect erlaubt einem Client, Tokens über /introspect zu prüfen.
+	ScopeIntrospect    = "introspect"
+	ScopePaymentsRead  = "payments:read"
+	ScopePaymentsWrite = "payments:write"
+	// ScopeUsersRead erlaubt GetUser und ListUsers über die gRPC-API.
+	ScopeUsersRead = "users:read"
+
+	// OpenID-Connect-Scopes für den Authorization-Code-Flow
+	ScopeOpenID  = "openid"
+	ScopeProfile = "profile"
+	ScopeEmail   = "email"
+)
+
+// ValidScope prüft, ob ein Scope an Clients vergeben werden darf.
+func ValidScope(scope string) bool {
+	switch scope {
+	case ScopeIntrospect, ScopePaymentsRead, ScopePaymentsWrite, ScopeUsersRead:
+		return true
+	}
+	return IsOIDCScope(scope)
+}
+
+// IsOIDCScope gilt für Scopes, die nur im Namen eines Users vergeben werden.
+func IsOIDCScope(scope string) bool {
+	switch scope {
+	case ScopeOpenID, ScopeProfile, ScopeEmail:
+		return true
+	}
+	return false
+}
+
+// Client ist ein Maschinen-Client (anderer Service, Tooling), der sich mit
+// client_id und Secret anmeldet. Gespeichert wird nur der Hash des Secrets.
+// Mit RedirectURIs ist er zusätzlich eine OIDC Relying Party; öffentliche
+// Clients (Public, z.B. Single-Page-Apps) haben kein Secret und sind allein
+// über PKCE abgesichert.
+type Client struct {
+	ID           string     `bson:"_id" json:"client_id"`
+	Name         string     `bson:"name" json:"name"`
+	SecretHash   string     `bson:"secret_hash" json:"-"`
+	Scopes       []string   `bson:"scopes" json:"scopes"`
+	RedirectURIs []string   `bson:"redirect_uris,omitempty" json:"redirect_uris,omitempty"`
+	Public       bool       `bson:"public,omitempty" json:"public,omitempty"`
+	Revoked      bool       `bson:"revoked" json:"revoked"`
+	CreatedBy    string     `bson:"created_by,omitempty" json:"created_by,omitempty"`
+	CreatedAt    time.Time  `bson:"created_at" json:"created_at"`
+	RotatedAt    *time.Time `bson:"rotated_at,omitempty" json:"rotated_at,omitempty"`
+}
+
+// ClientCredentials enthält das Secret im Klartext und wird nur beim Anlegen
+// und Rotieren einmalig zurückgegeben.
+type ClientCredentials struct {
+	Client       *Client `json:"client"`
+	ClientSecret string  `json:"client_secret,omitempty"`
+}
+
+// ServiceToken ist die Antwort auf den client_credentials Grant (RFC 6749 4.4).
+type ServiceToken struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int    `json:"expires_in"`
+	Scope       string `json:"scope"`
+}
+
+// HasRedirectURI vergleicht exakt, ohne Normalisierung.
+func (c *Client) HasRedirectURI(uri string) bool {
+	for _, u := range c.RedirectURIs {
+		if u == uri {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *Client) HasScope(scope string) bool {
+	for _, s := range c.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
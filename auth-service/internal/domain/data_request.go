@@ -0,0 +1,60 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Art der Betroffenenanfrage (DSGVO Art. 15 und 17)
+const (
+	DataRequestExport  = "export"
+	DataRequestErasure = "erasure"
+)
+
+// Status einer Anfrage und der einzelnen Services
+const (
+	DataRequestPending   = "pending"
+	DataRequestCompleted = "completed"
+	DataRequestFailed    = "failed"
+)
+
+// DataRequest verfolgt einen Export oder eine Löschung über alle Services.
+// Services enthält pro Service (z.B. "shopping-service") dessen Stand, auch
+// den des auth-service selbst.
+type DataRequest struct {
+	ID          string                              `bson:"_id" json:"id"`
+	Type        string                              `bson:"type" json:"type"`
+	UserID      string                              `bson:"user_id" json:"user_id"`
+	ActorID     string                              `bson:"actor_id" json:"actor_id"`
+	Status      string                              `bson:"status" json:"status"`
+	Services    map[string]DataRequestServiceStatus `bson:"services" json:"services"`
+	CreatedAt   time.Time                           `bson:"created_at" json:"created_at"`
+	CompletedAt *time.Time                          `bson:"completed_at,omitempty" json:"completed_at,omitempty"`
+	// Nach Ablauf löscht MongoDB die Anfrage samt Exportdaten.
+	ExpiresAt time.Time `bson:"expires_at" json:"expires_at"`
+}
+
+type DataRequestServiceStatus struct {
+	Status      string     `bson:"status" json:"status"`
+	Error       string     `bson:"error,omitempty" json:"error,omitempty"`
+	CompletedAt *time.Time `bson:"completed_at,omitempty" json:"completed_at,omitempty"`
+}
+
+// DataRequestResult ist die Rückmeldung eines Services auf dem Topic
+// data-request-events. Data enthält bei Exporten dessen Daten als JSON.
+type DataRequestResult struct {
+	RequestID string          `json:"request_id"`
+	Service   string          `json:"service"`
+	Type      string          `json:"type"`
+	Status    string          `json:"status"`
+	Error     string          `json:"error,omitempty"`
+	Data      json.RawMessage `json:"data,omitempty"`
+}
+
+// DataExportPart sind die exportierten Daten eines Services.
+type DataExportPart struct {
+	RequestID string    `bson:"request_id"`
+	Service   string    `bson:"service"`
+	Data      []byte    `bson:"data"`
+	ExpiresAt time.Time `bson:"expires_at"`
+}
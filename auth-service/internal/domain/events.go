@@ -0,0 +1,65 @@
+package domain
+
+import "time"
+
+const (
+	EventUserRegistered = "user_registered"
+	// EventUserLoggedIn folgt auf jeden abgeschlossenen Login (Data: method,
+	// restricted).
+	EventUserLoggedIn = "user_logged_in"
+	// EventLoginFailed enthält in Data den Grund (reason), z.B.
+	// invalid_password oder unknown_user.
+	EventLoginFailed = "login_failed"
+	EventUserLocked  = "user_locked"
+	// EventRoleChanged wird bei jeder Rollenänderung veröffentlicht (Data:
+	// old_role, new_role).
+	EventRoleChanged = "role_changed"
+	EventUserDeleted = "user_deleted"
+	// EventTokenRevoked sperrt ein einzelnes Access-Token (Data: jti, expires_at).
+	EventTokenRevoked = "token_revoked"
+	// EventUserTokensRevoked sperrt alle vor revoked_before ausgestellten
+	// Access-Tokens eines Users (Data: revoked_before, expires_at).
+	EventUserTokensRevoked = "user_tokens_revoked"
+	// EventClientRevoked sperrt alle Service-Tokens eines Clients bis zu ihrem
+	// Ablauf (Data: client_id, expires_at).
+	EventClientRevoked = "client_revoked"
+	// EventUserExportRequested und EventUserErasureRequested fordern die
+	// anderen Services auf, ihre Daten zu einem User zu exportieren bzw. zu
+	// anonymisieren (Data: request_id). Die Antwort kommt auf
+	// data-request-events.
+	EventUserExportRequested  = "user_export_requested"
+	EventUserErasureRequested = "user_erasure_requested"
+)
+
+// AuthEvent ist das gemeinsame Format aller Events auf dem auth-events Topic.
+// Dieselben Events landen im Audit-Log.
+type AuthEvent struct {
+	ID        string                 `bson:"_id,omitempty" json:"id,omitempty"`
+	EventType string                 `bson:"event_type" json:"event_type"`
+	UserID    string                 `bson:"user_id,omitempty" json:"user_id,omitempty"`
+	Email     string                 `bson:"email,omitempty" json:"email,omitempty"`
+	ActorID   string                 `bson:"actor_id,omitempty" json:"actor_id,omitempty"`
+	IP        string                 `bson:"ip,omitempty" json:"ip,omitempty"`
+	Data      map[string]interface{} `bson:"data,omitempty" json:"data,omitempty"`
+	Timestamp time.Time              `bson:"timestamp" json:"timestamp"`
+}
+
+// AuditFilter schränkt die Suche im Audit-Log ein; leere Felder filtern nicht.
+type AuditFilter struct {
+	EventType string
+	UserID    string
+	ActorID   string
+	Email     string
+	IP        string
+	From      *time.Time
+	To        *time.Time
+	Page      int
+	PageSize  int
+}
+
+type AuditPage struct {
+	Events   []AuthEvent `json:"events"`
+	Total    int64       `json:"total"`
+	Page     int         `json:"page"`
+	PageSize int         `json:"page_size"`
+}
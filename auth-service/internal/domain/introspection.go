@@ -0,0 +1,16 @@
+package domain
+
+// Introspection ist die Antwort von /introspect (RFC 7662). Bei ungültigen,
+// abgelaufenen oder widerrufenen Tokens ist nur Active gesetzt.
+type Introspection struct {
+	Active      bool     `json:"active"`
+	Subject     string   `json:"sub,omitempty"`
+	UserID      string   `json:"user_id,omitempty"`
+	Email       string   `json:"email,omitempty"`
+	Role        string   `json:"role,omitempty"`
+	Permissions []string `json:"permissions,omitempty"`
+	Type        string   `json:"typ,omitempty"`
+	JTI         string   `json:"jti,omitempty"`
+	IssuedAt    int64    `json:"iat,omitempty"`
+	ExpiresAt   int64    `json:"exp,omitempty"`
+}
@@ -0,0 +1,14 @@
+package domain
+
+type MFAEnrollment struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"`
+}
+
+// MFAActivation enthält die Recovery-Codes im Klartext. Sie werden nur dieses
+// eine Mal angezeigt. Erfolgte die Aktivierung im Login-Flow, sind auch die
+// Tokens gesetzt.
+type MFAActivation struct {
+	RecoveryCodes []string   `json:"recovery_codes"`
+	Tokens        *TokenPair `json:"tokens,omitempty"`
+}
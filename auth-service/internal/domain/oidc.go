@@ -0,0 +1,81 @@
+package domain
+
+import "time"
+
+// AuthorizationRequest sind die Parameter von /authorize.
+type AuthorizationRequest struct {
+	ClientID            string
+	RedirectURI         string
+	ResponseType        string
+	Scope               string
+	State               string
+	Nonce               string
+	CodeChallenge       string
+	CodeChallengeMethod string
+	Prompt              string
+}
+
+// AuthorizationCode ist nur einmal und sehr kurz einlösbar. Gespeichert wird
+// nur der Hash des Codes.
+type AuthorizationCode struct {
+	CodeHash      string    `bson:"_id"`
+	ClientID      string    `bson:"client_id"`
+	UserID        string    `bson:"user_id"`
+	RedirectURI   string    `bson:"redirect_uri"`
+	Scopes        []string  `bson:"scopes"`
+	Nonce         string    `bson:"nonce,omitempty"`
+	CodeChallenge string    `bson:"code_challenge"`
+	AuthTime      time.Time `bson:"auth_time"`
+	ExpiresAt     time.Time `bson:"expires_at"`
+}
+
+// Consent hält fest, welche Scopes ein User einem Client freigegeben hat.
+type Consent struct {
+	UserID    string    `bson:"user_id" json:"user_id"`
+	ClientID  string    `bson:"client_id" json:"client_id"`
+	Scopes    []string  `bson:"scopes" json:"scopes"`
+	GrantedAt time.Time `bson:"granted_at" json:"granted_at"`
+}
+
+// Covers prüft, ob alle angefragten Scopes bereits freigegeben sind.
+func (c *Consent) Covers(scopes []string) bool {
+	for _, scope := range scopes {
+		found := false
+		for _, granted := range c.Scopes {
+			if granted == scope {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// OIDCTokenResponse ist die Antwort von /token auf den authorization_code Grant.
+type OIDCTokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int    `json:"expires_in"`
+	Scope       string `json:"scope"`
+	IDToken     string `json:"id_token"`
+}
+
+// OIDCDiscovery ist das Dokument unter /.well-known/openid-configuration.
+type OIDCDiscovery struct {
+	Issuer                            string   `json:"issuer"`
+	AuthorizationEndpoint             string   `json:"authorization_endpoint"`
+	TokenEndpoint                     string   `json:"token_endpoint"`
+	UserinfoEndpoint                  string   `json:"userinfo_endpoint"`
+	JWKSURI                           string   `json:"jwks_uri"`
+	ScopesSupported                   []string `json:"scopes_supported"`
+	ResponseTypesSupported            []string `json:"response_types_supported"`
+	GrantTypesSupported               []string `json:"grant_types_supported"`
+	SubjectTypesSupported             []string `json:"subject_types_supported"`
+	IDTokenSigningAlgValuesSupported  []string `json:"id_token_signing_alg_values_supported"`
+	TokenEndpointAuthMethodsSupported []string `json:"token_endpoint_auth_methods_supported"`
+	CodeChallengeMethodsSupported     []string `json:"code_challenge_methods_supported"`
+	ClaimsSupported                   []string `json:"claims_supported"`
+}
@@ -0,0 +1,23 @@
+package domain
+
+import "time"
+
+const (
+	TokenPurposePasswordReset     = "password_reset"
+	TokenPurposeEmailVerification = "email_verification"
+	TokenPurposeEmailChange       = "email_change"
+)
+
+// OneTimeToken wird per Mail verschickt und kann genau einmal vor ExpiresAt
+// eingelöst werden. Gespeichert wird nur der Hash.
+type OneTimeToken struct {
+	ID      string `bson:"_id,omitempty" json:"id"`
+	UserID  string `bson:"user_id" json:"user_id"`
+	Purpose string `bson:"purpose" json:"purpose"`
+	// Email ist bei email_change die neue, noch zu bestätigende Adresse.
+	Email     string     `bson:"email,omitempty" json:"email,omitempty"`
+	TokenHash string     `bson:"token_hash" json:"-"`
+	ExpiresAt time.Time  `bson:"expires_at" json:"expires_at"`
+	CreatedAt time.Time  `bson:"created_at" json:"created_at"`
+	UsedAt    *time.Time `bson:"used_at,omitempty" json:"used_at,omitempty"`
+}
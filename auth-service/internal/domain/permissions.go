@@ -0,0 +1,48 @@
+package domain
+
+import "slices"
+
+// Berechtigungen im Format ressource:aktion, optional mit Bereich (":any" =
+// auch fremde Datensätze). Sie landen im Claim "permissions" und werden von
+// allen Services statt der Rolle geprüft.
+const (
+	PermProductsWrite    = "products:write"
+	PermProductsWriteAny = "products:write:any"
+	PermCategoriesManage = "categories:manage"
+	PermOrdersReadAny    = "orders:read:any"
+	PermPaymentsRefund   = "payments:refund"
+	PermUsersRead        = "users:read"
+	PermUsersWrite       = "users:write"
+	PermRolesManage      = "roles:manage"
+	PermAuditRead        = "audit:read"
+	PermClientsManage    = "clients:manage"
+)
+
+// rolePermissions legt fest, welche Rollen es gibt und was sie dürfen. Eine
+// neue Rolle braucht nur einen Eintrag hier.
+var rolePermissions = map[string][]string{
+	RoleUser:    {},
+	RoleSupport: {PermUsersRead, PermOrdersReadAny, PermAuditRead},
+	RoleSeller:  {PermProductsWrite, PermOrdersReadAny},
+	RoleAdmin: {
+		PermProductsWrite, PermProductsWriteAny, PermCategoriesManage, PermOrdersReadAny, PermPaymentsRefund,
+		PermUsersRead, PermUsersWrite, PermRolesManage, PermAuditRead, PermClientsManage,
+	},
+}
+
+// ValidRole prüft, ob eine Rolle vergeben werden darf.
+func ValidRole(role string) bool {
+	_, ok := rolePermissions[role]
+	return ok
+}
+
+// PermissionsForRole liefert eine Kopie der Berechtigungen, bei unbekannten
+// Rollen keine.
+func PermissionsForRole(role string) []string {
+	return slices.Clone(rolePermissions[role])
+}
+
+// HasPermission prüft eine einzelne Berechtigung.
+func (c *Claims) HasPermission(permission string) bool {
+	return slices.Contains(c.Permissions, permission)
+}
@@ -0,0 +1,21 @@
+package domain
+
+// Address ist eine Lieferadresse im Profil. Höchstens eine ist Default und
+// wird vom Checkout verwendet, wenn keine andere gewählt wird.
+type Address struct {
+	ID         string `bson:"id" json:"id"`
+	Label      string `bson:"label,omitempty" json:"label,omitempty"`
+	Name       string `bson:"name" json:"name"`
+	Street     string `bson:"street" json:"street"`
+	PostalCode string `bson:"postal_code" json:"postal_code"`
+	City       string `bson:"city" json:"city"`
+	Country    string `bson:"country" json:"country"`
+	Default    bool   `bson:"default" json:"default"`
+}
+
+// ProfileUpdate enthält die Felder von PATCH /me; nil bleibt unverändert.
+type ProfileUpdate struct {
+	DisplayName       *string    `json:"display_name"`
+	PreferredCurrency *string    `json:"preferred_currency"`
+	Addresses         *[]Address `json:"addresses"`
+}
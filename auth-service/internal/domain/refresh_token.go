@@ -0,0 +1,30 @@
+package domain
+
+import "time"
+
+// RefreshToken ist ein langlebiges, einmal verwendbares Token. Alle Tokens,
+// die durch Rotation auseinander hervorgehen, teilen sich eine FamilyID.
+type RefreshToken struct {
+	ID         string     `bson:"_id" json:"id"`
+	UserID     string     `bson:"user_id" json:"user_id"`
+	FamilyID   string     `bson:"family_id" json:"family_id"`
+	TokenHash  string     `bson:"token_hash" json:"-"`
+	ExpiresAt  time.Time  `bson:"expires_at" json:"expires_at"`
+	CreatedAt  time.Time  `bson:"created_at" json:"created_at"`
+	UsedAt     *time.Time `bson:"used_at,omitempty" json:"used_at,omitempty"`
+	ReplacedBy string     `bson:"replaced_by,omitempty" json:"replaced_by,omitempty"`
+	Revoked    bool       `bson:"revoked" json:"revoked"`
+}
+
+// TokenPair wird nach Login und Refresh an den Client zurückgegeben.
+// Ist eine zweite Stufe nötig, enthält es statt der Tokens nur MFAToken.
+type TokenPair struct {
+	AccessToken  string `json:"token,omitempty"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+	ExpiresIn    int    `json:"expires_in"`
+	Restricted   bool   `json:"restricted,omitempty"`
+
+	MFARequired           bool   `json:"mfa_required,omitempty"`
+	MFAToken              string `json:"mfa_token,omitempty"`
+	MFAEnrollmentRequired bool   `json:"mfa_enrollment_required,omitempty"`
+}
@@ -0,0 +1,20 @@
+package domain
+
+import "time"
+
+// RevokedToken sperrt ein einzelnes Access-Token (jti) bis zu seinem Ablauf.
+type RevokedToken struct {
+	JTI       string    `bson:"_id" json:"jti"`
+	UserID    string    `bson:"user_id" json:"user_id"`
+	ExpiresAt time.Time `bson:"expires_at" json:"expires_at"`
+	RevokedAt time.Time `bson:"revoked_at" json:"revoked_at"`
+}
+
+// UserTokenRevocation sperrt alle Access-Tokens eines Users, die vor
+// RevokedBefore ausgestellt wurden. Nach ExpiresAt ist kein solches Token mehr
+// gültig und der Eintrag kann entfallen.
+type UserTokenRevocation struct {
+	UserID        string    `bson:"_id" json:"user_id"`
+	RevokedBefore time.Time `bson:"revoked_before" json:"revoked_before"`
+	ExpiresAt     time.Time `bson:"expires_at" json:"expires_at"`
+}
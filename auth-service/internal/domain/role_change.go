@@ -0,0 +1,13 @@
+package domain
+
+import "time"
+
+// RoleChange protokolliert jede Rollenänderung mit Urheber und Zeitpunkt.
+type RoleChange struct {
+	ID        string    `bson:"_id,omitempty" json:"id"`
+	UserID    string    `bson:"user_id" json:"user_id"`
+	OldRole   string    `bson:"old_role" json:"old_role"`
+	NewRole   string    `bson:"new_role" json:"new_role"`
+	ChangedBy string    `bson:"changed_by" json:"changed_by"`
+	ChangedAt time.Time `bson:"changed_at" json:"changed_at"`
+}
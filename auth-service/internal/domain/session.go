@@ -0,0 +1,23 @@
+package domain
+
+import "time"
+
+// Session ist eine Anmeldung auf einem Gerät. Die ID ist die FamilyID der
+// Refresh-Tokens, die aus diesem Login hervorgehen, und steht als sid im
+// Access-Token. LastSeenAt wird bei Login und Refresh aktualisiert.
+type Session struct {
+	ID         string    `bson:"_id" json:"id"`
+	UserID     string    `bson:"user_id" json:"user_id"`
+	UserAgent  string    `bson:"user_agent" json:"user_agent"`
+	IP         string    `bson:"ip" json:"ip"`
+	CreatedAt  time.Time `bson:"created_at" json:"created_at"`
+	LastSeenAt time.Time `bson:"last_seen_at" json:"last_seen_at"`
+	ExpiresAt  time.Time `bson:"expires_at" json:"expires_at"`
+	// Das zuletzt ausgestellte Access-Token, damit es beim Beenden der
+	// Sitzung sofort gesperrt werden kann.
+	AccessJTI       string    `bson:"access_jti" json:"-"`
+	AccessExpiresAt time.Time `bson:"access_expires_at" json:"-"`
+
+	// Current markiert in /me/sessions die Sitzung des Aufrufers.
+	Current bool `bson:"-" json:"current"`
+}
@@ -1,8 +1,68 @@
 package domain
 
+import (
+	"strings"
+	"time"
+)
+
+const (
+	RoleUser  = "user"
+	RoleAdmin = "admin"
+	// RoleSupport darf Kundendaten und Bestellungen einsehen, aber nichts ändern.
+	RoleSupport = "support"
+	// RoleSeller pflegt Produkte und sieht die zugehörigen Bestellungen.
+	RoleSeller = "seller"
+)
+
 type User struct {
 	ID       string `bson:"_id,omitempty" json:"id"`
 	Email    string `bson:"email" json:"email"`
 	Password string `bson:"password" json:"-"`
-	Role     string `bson:"role" json:"role"` // "admin" oder "user"
+	Role     string `bson:"role" json:"role"` // siehe rolePermissions
+	Verified bool   `bson:"verified" json:"verified"`
+	// CreatedAt fehlt bei alten Konten und wird dann aus der ObjectID gelesen.
+	CreatedAt time.Time `bson:"created_at,omitempty" json:"created_at"`
+
+	Disabled              bool       `bson:"disabled,omitempty" json:"disabled"`
+	DisabledAt            *time.Time `bson:"disabled_at,omitempty" json:"disabled_at,omitempty"`
+	PasswordResetRequired bool       `bson:"password_reset_required,omitempty" json:"password_reset_required"`
+
+	DisplayName       string    `bson:"display_name,omitempty" json:"display_name,omitempty"`
+	Addresses         []Address `bson:"addresses,omitempty" json:"addresses"`
+	PreferredCurrency string    `bson:"preferred_currency,omitempty" json:"preferred_currency,omitempty"`
+
+	FailedLogins    int        `bson:"failed_logins" json:"failed_logins"`
+	LastFailedLogin *time.Time `bson:"last_failed_login,omitempty" json:"-"`
+	LockedUntil     *time.Time `bson:"locked_until,omitempty" json:"locked_until,omitempty"`
+
+	MFAEnabled        bool     `bson:"mfa_enabled" json:"mfa_enabled"`
+	TOTPSecret        string   `bson:"totp_secret,omitempty" json:"-"`
+	PendingTOTPSecret string   `bson:"pending_totp_secret,omitempty" json:"-"`
+	LastTOTPCounter   int64    `bson:"last_totp_counter,omitempty" json:"-"`
+	RecoveryCodes     []string `bson:"recovery_codes,omitempty" json:"-"` // SHA-256 Hashes
+}
+
+// NormalizeEmail ist die Form, in der E-Mail-Adressen gespeichert und
+// gesucht werden.
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// MFARequired ist für Admins immer wahr, für alle anderen nur nach Aktivierung.
+func (u *User) MFARequired() bool {
+	return u.MFAEnabled || u.Role == RoleAdmin
+}
+
+// Status fasst die Felder für die Admin-Übersicht zusammen.
+func (u *User) Status() string {
+	switch {
+	case u.Disabled:
+		return UserStatusDisabled
+	case u.LockedUntil != nil && time.Now().Before(*u.LockedUntil):
+		return UserStatusLocked
+	case !u.Verified:
+		return UserStatusUnverified
+	default:
+		return UserStatusActive
+	}
 }
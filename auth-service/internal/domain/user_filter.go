@@ -0,0 +1,37 @@
+package domain
+
+import "time"
+
+// Werte für UserFilter.Status
+const (
+	UserStatusActive     = "active"
+	UserStatusDisabled   = "disabled"
+	UserStatusLocked     = "locked"
+	UserStatusUnverified = "unverified"
+)
+
+// ValidUserStatus prüft einen Statusfilter.
+func ValidUserStatus(status string) bool {
+	switch status {
+	case UserStatusActive, UserStatusDisabled, UserStatusLocked, UserStatusUnverified:
+		return true
+	}
+	return false
+}
+
+// UserFilter schränkt die Admin-Liste ein; leere Felder filtern nicht.
+type UserFilter struct {
+	Role          string
+	Status        string
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+	Page          int
+	PageSize      int
+}
+
+type UserPage struct {
+	Users    []User `json:"users"`
+	Total    int64  `json:"total"`
+	Page     int    `json:"page"`
+	PageSize int    `json:"page_size"`
+}
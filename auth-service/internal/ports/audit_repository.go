@@ -0,0 +1,14 @@
+package ports
+
+import (
+	"auth-service/internal/domain"
+	"context"
+)
+
+type AuditRepository interface {
+	Append(ctx context.Context, event *domain.AuthEvent) error
+	// Find liefert die passenden Events, neueste zuerst, und die Gesamtzahl.
+	Find(ctx context.Context, filter domain.AuditFilter) ([]domain.AuthEvent, int64, error)
+	// AnonymizeUser entfernt E-Mail und IP aus allen Events eines Users.
+	AnonymizeUser(ctx context.Context, userID string) error
+}
@@ -0,0 +1,18 @@
+package ports
+
+import (
+	"auth-service/internal/domain"
+	"time"
+)
+
+type ClientRepository interface {
+	// Save legt den Client an oder überschreibt ihn.
+	Save(client *domain.Client) error
+	// CreateIfMissing legt den Client nur an, wenn die ID noch frei ist, und
+	// meldet, ob er angelegt wurde.
+	CreateIfMissing(client *domain.Client) (bool, error)
+	FindByID(id string) (*domain.Client, error)
+	FindAll() ([]domain.Client, error)
+	UpdateSecret(id, secretHash string, rotatedAt time.Time) error
+	Revoke(id string) error
+}
@@ -0,0 +1,18 @@
+package ports
+
+import (
+	"auth-service/internal/domain"
+	"context"
+)
+
+type DataRequestRepository interface {
+	Create(ctx context.Context, request *domain.DataRequest) error
+	FindByID(ctx context.Context, id string) (*domain.DataRequest, error)
+	// SetServiceStatus aktualisiert den Stand eines Services und liefert die
+	// Anfrage danach.
+	SetServiceStatus(ctx context.Context, id, service string, status domain.DataRequestServiceStatus) (*domain.DataRequest, error)
+	// Finish setzt den Gesamtstatus, solange die Anfrage noch offen ist.
+	Finish(ctx context.Context, id, status string) error
+	SaveExportPart(ctx context.Context, part *domain.DataExportPart) error
+	FindExportParts(ctx context.Context, requestID string) ([]domain.DataExportPart, error)
+}
@@ -0,0 +1,10 @@
+package ports
+
+import (
+	"auth-service/internal/domain"
+	"context"
+)
+
+type EventPublisher interface {
+	Publish(ctx context.Context, event *domain.AuthEvent) error
+}
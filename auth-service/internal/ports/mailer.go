@@ -0,0 +1,7 @@
+package ports
+
+import "auth-service/internal/domain"
+
+type Mailer interface {
+	Send(mail *domain.Mail) error
+}
@@ -0,0 +1,18 @@
+package ports
+
+import (
+	"auth-service/internal/domain"
+	"context"
+)
+
+type ConsentRepository interface {
+	Find(ctx context.Context, userID, clientID string) (*domain.Consent, error)
+	// Save legt die Freigabe an oder ersetzt sie.
+	Save(ctx context.Context, consent *domain.Consent) error
+}
+
+type AuthorizationCodeRepository interface {
+	Create(ctx context.Context, code *domain.AuthorizationCode) error
+	// Consume liefert den Code und löscht ihn in einem Schritt.
+	Consume(ctx context.Context, codeHash string) (*domain.AuthorizationCode, error)
+}
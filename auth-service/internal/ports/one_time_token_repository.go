@@ -0,0 +1,11 @@
+package ports
+
+import "auth-service/internal/domain"
+
+type OneTimeTokenRepository interface {
+	Create(token *domain.OneTimeToken) error
+	// Consume löst ein unbenutztes, nicht abgelaufenes Token atomar ein.
+	Consume(hash, purpose string) (*domain.OneTimeToken, error)
+	InvalidateForUser(userID, purpose string) error
+	LatestForUser(userID, purpose string) (*domain.OneTimeToken, error)
+}
@@ -0,0 +1,13 @@
+package ports
+
+import "auth-service/internal/domain"
+
+type RefreshTokenRepository interface {
+	Create(token *domain.RefreshToken) error
+	FindByHash(hash string) (*domain.RefreshToken, error)
+	// MarkUsed markiert das Token als verbraucht. Gibt false zurück, wenn es
+	// bereits verbraucht oder widerrufen war.
+	MarkUsed(id, replacedBy string) (bool, error)
+	RevokeFamily(familyID string) error
+	RevokeAllForUser(userID string) error
+}
@@ -0,0 +1,14 @@
+package ports
+
+import (
+	"auth-service/internal/domain"
+	"time"
+)
+
+type RevocationRepository interface {
+	RevokeToken(token *domain.RevokedToken) error
+	RevokeUserTokens(revocation *domain.UserTokenRevocation) error
+	// IsRevoked prüft, ob das Token selbst oder alle bis issuedAt
+	// ausgestellten Tokens des Users widerrufen wurden.
+	IsRevoked(jti, userID string, issuedAt time.Time) (bool, error)
+}
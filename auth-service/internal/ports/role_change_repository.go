@@ -0,0 +1,8 @@
+package ports
+
+import "auth-service/internal/domain"
+
+type RoleChangeRepository interface {
+	Create(change *domain.RoleChange) error
+	FindByUser(userID string) ([]domain.RoleChange, error)
+}
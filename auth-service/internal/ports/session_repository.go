@@ -0,0 +1,17 @@
+package ports
+
+import (
+	"auth-service/internal/domain"
+	"context"
+)
+
+type SessionRepository interface {
+	// Save legt die Sitzung an oder aktualisiert sie; CreatedAt wird nur beim
+	// Anlegen gesetzt.
+	Save(ctx context.Context, session *domain.Session) error
+	FindByID(ctx context.Context, id string) (*domain.Session, error)
+	// FindByUser liefert die nicht abgelaufenen Sitzungen, zuletzt aktive zuerst.
+	FindByUser(ctx context.Context, userID string) ([]domain.Session, error)
+	Delete(ctx context.Context, id string) error
+	DeleteAllForUser(ctx context.Context, userID string) error
+}
@@ -1,8 +1,49 @@
 package ports
 
-import "auth-service/internal/domain"
+import (
+	"auth-service/internal/domain"
+	"context"
+	"errors"
+	"time"
+)
 
+// ErrUserExists meldet Create bzw. UpdateEmail, wenn die E-Mail-Adresse schon
+// vergeben ist.
+var ErrUserExists = errors.New("user already exists")
+
+// UserRepository speichert und sucht E-Mail-Adressen immer normalisiert (siehe
+// domain.NormalizeEmail).
 type UserRepository interface {
-	Create(user *domain.User) error
-	FindByEmail(email string) (*domain.User, error)
+	Create(ctx context.Context, user *domain.User) error
+	FindByEmail(ctx context.Context, email string) (*domain.User, error)
+	FindByID(ctx context.Context, id string) (*domain.User, error)
+	// List liefert die Seite filter.Page (ab 1) und die Gesamtzahl der Treffer.
+	List(ctx context.Context, filter domain.UserFilter) ([]domain.User, int64, error)
+	SetDisabled(ctx context.Context, id string, disabled bool) error
+	RequirePasswordReset(ctx context.Context, id string) error
+	Delete(ctx context.Context, id string) error
+	UpdateProfile(ctx context.Context, id string, update domain.ProfileUpdate) error
+	// UpdateEmail setzt die neue, bereits bestätigte Adresse.
+	UpdateEmail(ctx context.Context, id, email string) error
+	UpdateRole(ctx context.Context, id, role string) error
+	// UpdatePassword hebt auch eine erzwungene Passwortänderung auf.
+	UpdatePassword(ctx context.Context, id, passwordHash string) error
+	// ReplacePasswordHash tauscht den Hash nur aus, wenn noch oldHash
+	// gespeichert ist. Wurde das Passwort inzwischen geändert, passiert nichts.
+	ReplacePasswordHash(ctx context.Context, id, oldHash, newHash string) error
+	MarkVerified(ctx context.Context, id string) error
+	// BeginLoginAttempt erhöht den Zähler fehlgeschlagener Logins atomar und
+	// gibt den neuen Stand zurück, aber nur, wenn noch seen gespeichert ist.
+	// Sonst ist ok false, weil parallel ein anderer Versuch gezählt wurde.
+	BeginLoginAttempt(ctx context.Context, id string, seen int) (failures int, ok bool, err error)
+	LockUntil(ctx context.Context, id string, until time.Time) error
+	ResetFailedLogins(ctx context.Context, id string) error
+	SetPendingTOTPSecret(ctx context.Context, id, secret string) error
+	EnableTOTP(ctx context.Context, id, secret string, recoveryCodeHashes []string) error
+	DisableTOTP(ctx context.Context, id string) error
+	// AdvanceTOTPCounter speichert den zuletzt verwendeten Zeitschritt und
+	// gibt false zurück, wenn er nicht neuer ist (wiederverwendeter Code).
+	AdvanceTOTPCounter(ctx context.Context, id string, counter int64) (bool, error)
+	// UseRecoveryCode entfernt den Code und gibt false zurück, wenn er nicht existiert.
+	UseRecoveryCode(ctx context.Context, id, codeHash string) (bool, error)
 }
@@ -0,0 +1,66 @@
+package service
+
+import (
+	"auth-service/internal/domain"
+	"auth-service/internal/ports"
+	"context"
+	"errors"
+	"log"
+)
+
+const (
+	defaultAuditPageSize = 50
+	maxAuditPageSize     = 200
+)
+
+var ErrInvalidAuditFilter = errors.New("invalid audit filter")
+
+// AuditService macht das Audit-Log für Admins durchsuchbar.
+type AuditService struct {
+	audit ports.AuditRepository
+}
+
+func NewAuditService(audit ports.AuditRepository) *AuditService {
+	return &AuditService{audit: audit}
+}
+
+func (s *AuditService) Search(ctx context.Context, filter domain.AuditFilter) (*domain.AuditPage, error) {
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		return nil, ErrInvalidAuditFilter
+	}
+	if filter.Page < 1 {
+		filter.Page = 1
+	}
+	if filter.PageSize < 1 {
+		filter.PageSize = defaultAuditPageSize
+	}
+	if filter.PageSize > maxAuditPageSize {
+		filter.PageSize = maxAuditPageSize
+	}
+
+	events, total, err := s.audit.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.AuditPage{Events: events, Total: total, Page: filter.Page, PageSize: filter.PageSize}, nil
+}
+
+// auditedPublisher schreibt jedes Event ins Audit-Log, bevor es auf Kafka
+// veröffentlicht wird. Ist der Broker nicht erreichbar, bleibt der Eintrag im
+// Audit-Log trotzdem erhalten.
+type auditedPublisher struct {
+	audit ports.AuditRepository
+	next  ports.EventPublisher
+}
+
+func NewAuditedPublisher(audit ports.AuditRepository, next ports.EventPublisher) ports.EventPublisher {
+	return &auditedPublisher{audit: audit, next: next}
+}
+
+func (p *auditedPublisher) Publish(ctx context.Context, event *domain.AuthEvent) error {
+	auditErr := p.audit.Append(ctx, event)
+	if auditErr != nil {
+		log.Printf("Failed to write %s event to audit log: %v", event.EventType, auditErr)
+	}
+	return errors.Join(auditErr, p.next.Publish(ctx, event))
+}
@@ -3,75 +3,465 @@ package service
 import (
 	"auth-service/internal/domain"
 	"auth-service/internal/ports"
+	"context"
 	"errors"
+	"log"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
-	"golang.org/x/crypto/bcrypt"
 )
 
+var (
+	ErrInvalidCredentials  = errors.New("invalid credentials")
+	ErrInvalidRefreshToken = errors.New("invalid refresh token")
+	ErrRefreshTokenReused  = errors.New("refresh token reuse detected")
+	ErrInvalidToken        = errors.New("invalid token")
+	ErrEmailNotVerified    = errors.New("email not verified")
+	ErrAccountDisabled     = errors.New("account disabled")
+	// ErrPasswordResetRequired: ein Admin hat eine Passwortänderung erzwungen,
+	// der Login ist erst nach /password/reset wieder möglich.
+	ErrPasswordResetRequired = errors.New("password reset required")
+	ErrUserExists            = ports.ErrUserExists
+)
+
+// Werte für AuthConfig.UnverifiedLogin
+const (
+	UnverifiedLoginDeny       = "deny"
+	UnverifiedLoginRestricted = "restricted"
+)
+
+// AuthConfig bündelt die Einstellungen des AuthService aus der Umgebung.
+type AuthConfig struct {
+	AccessExpiry  time.Duration
+	RefreshExpiry time.Duration
+	// UnverifiedLogin legt fest, ob unbestätigte Konten abgewiesen werden
+	// ("deny") oder ein eingeschränktes Token ohne Refresh-Token erhalten
+	// ("restricted").
+	UnverifiedLogin string
+	Lockout         LockoutConfig
+	// MFAIssuer erscheint in der Authenticator-App als Name des Kontos.
+	MFAIssuer        string
+	MFAPendingExpiry time.Duration
+}
+
 type AuthService struct {
-	repo      ports.UserRepository
-	jwtSecret string
-	expiry    time.Duration
-	JWTSecret string
+	repo          ports.UserRepository
+	refreshTokens ports.RefreshTokenRepository
+	revocations   ports.RevocationRepository
+	sessions      ports.SessionRepository
+	verification  *VerificationService
+	events        ports.EventPublisher
+	keys          *KeySet
+	hasher        PasswordHasher
+	policy        *PasswordPolicy
+	cfg           AuthConfig
+	ipThrottle    *ipThrottle
 }
 
-func (s *AuthService) Authenticate(email, password string) (*domain.User, error) {
-	user, err := s.repo.FindByEmail(email)
+// Authenticate prüft die Zugangsdaten. Fehlversuche werden pro Konto und pro
+// IP gezählt; nach zu vielen Versuchen wird mit LoginBlockedError abgelehnt.
+func (s *AuthService) Authenticate(ctx context.Context, email, password, ip string) (*domain.User, error) {
+	email = domain.NormalizeEmail(email)
+	if err := s.ipThrottle.check(ip); err != nil {
+		return nil, err
+	}
+
+	user, err := s.repo.FindByEmail(ctx, email)
+	if err != nil {
+		s.ipThrottle.fail(ip)
+		s.loginFailed(&domain.User{Email: email}, ip, "unknown_user")
+		return nil, ErrInvalidCredentials
+	}
+
+	failures, err := s.beginLoginAttempt(ctx, user)
 	if err != nil {
-		return nil, errors.New("invalid credentials")
+		s.loginFailed(user, ip, "blocked")
+		return nil, err
 	}
 
-	if bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)) != nil {
-		return nil, errors.New("invalid credentials")
+	if !s.hasher.Verify(user.Password, password) {
+		s.ipThrottle.fail(ip)
+		s.loginFailed(user, ip, "invalid_password")
+		s.lockIfExceeded(ctx, user, failures, ip)
+		return nil, ErrInvalidCredentials
+	}
+	s.resetFailedLogins(ctx, user)
+
+	if user.Disabled {
+		s.loginFailed(user, ip, "account_disabled")
+		return nil, ErrAccountDisabled
+	}
+	if user.PasswordResetRequired {
+		s.loginFailed(user, ip, "password_reset_required")
+		return nil, ErrPasswordResetRequired
+	}
+	if !user.Verified && s.cfg.UnverifiedLogin != UnverifiedLoginRestricted {
+		s.loginFailed(user, ip, "email_not_verified")
+		return nil, ErrEmailNotVerified
 	}
 
+	s.rehashPassword(ctx, user, password)
 	return user, nil
 }
 
-func NewAuthService(repo ports.UserRepository, jwtSecret string, expirySeconds int) *AuthService {
+// rehashPassword ersetzt Hashes mit altem Verfahren oder schwachen Parametern.
+// Das Klartextpasswort steht nur beim Login zur Verfügung, deshalb passiert
+// das hier. Fehler verhindern den Login nicht.
+func (s *AuthService) rehashPassword(ctx context.Context, user *domain.User, password string) {
+	if !s.hasher.NeedsRehash(user.Password) {
+		return
+	}
+	hash, err := s.hasher.Hash(password)
+	if err != nil {
+		log.Printf("Failed to rehash password for user %s: %v", user.ID, err)
+		return
+	}
+	if err := s.repo.ReplacePasswordHash(ctx, user.ID, user.Password, hash); err != nil {
+		log.Printf("Failed to store rehashed password for user %s: %v", user.ID, err)
+		return
+	}
+	user.Password = hash
+}
+
+func NewAuthService(repo ports.UserRepository, refreshTokens ports.RefreshTokenRepository, revocations ports.RevocationRepository, sessions ports.SessionRepository, verification *VerificationService, events ports.EventPublisher, keys *KeySet, hasher PasswordHasher, policy *PasswordPolicy, cfg AuthConfig) *AuthService {
 	return &AuthService{
-		repo:      repo,
-		jwtSecret: jwtSecret,
-		expiry:    time.Duration(expirySeconds) * time.Second,
+		repo:          repo,
+		refreshTokens: refreshTokens,
+		revocations:   revocations,
+		sessions:      sessions,
+		verification:  verification,
+		events:        events,
+		keys:          keys,
+		hasher:        hasher,
+		policy:        policy,
+		cfg:           cfg,
+		ipThrottle:    newIPThrottle(cfg.Lockout.MaxFailuresPerIP, cfg.Lockout.IPWindow),
 	}
 }
 
-func (s *AuthService) Register(email, password, role string) error {
-	_, err := s.repo.FindByEmail(email)
+func (s *AuthService) emit(event *domain.AuthEvent) {
+	publishAsync(s.events, event)
+}
+
+// loginFailed protokolliert einen abgewiesenen Login. Ablehnungen durch das
+// IP-Limit werden nicht einzeln erfasst, damit ein Angriff das Audit-Log nicht
+// flutet.
+func (s *AuthService) loginFailed(user *domain.User, ip, reason string) {
+	s.emit(&domain.AuthEvent{
+		EventType: domain.EventLoginFailed,
+		UserID:    user.ID,
+		Email:     user.Email,
+		IP:        ip,
+		Data:      map[string]interface{}{"reason": reason},
+	})
+}
+
+// loggedIn protokolliert einen abgeschlossenen Login; method ist password oder mfa.
+func (s *AuthService) loggedIn(user *domain.User, pair *domain.TokenPair, ip, method string) {
+	s.emit(&domain.AuthEvent{
+		EventType: domain.EventUserLoggedIn,
+		UserID:    user.ID,
+		Email:     user.Email,
+		IP:        ip,
+		Data: map[string]interface{}{
+			"method":     method,
+			"restricted": pair.Restricted,
+		},
+	})
+}
+
+// Register legt immer einen normalen User an. Rollen werden ausschließlich
+// über den RoleService von Admins vergeben.
+func (s *AuthService) Register(ctx context.Context, email, password, ip string) error {
+	email = domain.NormalizeEmail(email)
+	_, err := s.repo.FindByEmail(ctx, email)
 	if err == nil {
-		return errors.New("user already exists")
+		return ErrUserExists
+	}
+	if err := s.policy.Check(password); err != nil {
+		return err
 	}
 
-	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	hash, err := s.hasher.Hash(password)
 	if err != nil {
 		return err
 	}
 
 	user := &domain.User{
 		Email:    email,
-		Password: string(hash),
-		Role:     role, // <-- Rolle aus Parameter!
+		Password: hash,
+		Role:     domain.RoleUser,
+	}
+	// Der eindeutige Index fängt gleichzeitige Registrierungen ab
+	if err := s.repo.Create(ctx, user); err != nil {
+		return err
+	}
+	s.emit(&domain.AuthEvent{
+		EventType: domain.EventUserRegistered,
+		UserID:    user.ID,
+		Email:     user.Email,
+		IP:        ip,
+	})
+
+	if err := s.verification.SendVerification(user); err != nil {
+		// Der User existiert bereits und kann über /verify-email/resend einen
+		// neuen Link anfordern.
+		log.Printf("Failed to send verification mail to %s: %v", user.Email, err)
+	}
+	return nil
+}
+
+// Login prüft die Zugangsdaten. Für Admins und Konten mit aktivierter
+// Zwei-Faktor-Authentifizierung gibt es zunächst nur ein mfa_pending Token.
+func (s *AuthService) Login(ctx context.Context, email, password, ip, userAgent string) (*domain.TokenPair, error) {
+	user, err := s.Authenticate(ctx, email, password, ip)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.MFARequired() {
+		return s.startMFA(user)
+	}
+	pair, err := s.completeLogin(ctx, user, ip, userAgent)
+	if err != nil {
+		return nil, err
+	}
+	s.loggedIn(user, pair, ip, "password")
+	return pair, nil
+}
+
+// completeLogin stellt nach erfolgreicher Anmeldung die Tokens aus und legt
+// eine neue Sitzung an.
+func (s *AuthService) completeLogin(ctx context.Context, user *domain.User, ip, userAgent string) (*domain.TokenPair, error) {
+	if !user.Verified {
+		accessToken, err := s.signAccessToken(user, domain.TokenTypeUnverified)
+		if err != nil {
+			return nil, err
+		}
+		return &domain.TokenPair{
+			AccessToken: accessToken,
+			ExpiresIn:   int(s.cfg.AccessExpiry.Seconds()),
+			Restricted:  true,
+		}, nil
+	}
+
+	familyID, err := newID()
+	if err != nil {
+		return nil, err
+	}
+	return s.issueTokens(ctx, user, familyID, ip, userAgent)
+}
+
+// Refresh tauscht ein Refresh-Token gegen ein neues Token-Paar. Jedes
+// Refresh-Token ist nur einmal gültig; wird ein bereits rotiertes Token erneut
+// vorgelegt, gilt die ganze Familie als kompromittiert und wird widerrufen.
+func (s *AuthService) Refresh(ctx context.Context, refreshToken, ip, userAgent string) (*domain.TokenPair, error) {
+	stored, err := s.refreshTokens.FindByHash(hashToken(refreshToken))
+	if err != nil {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	if stored.UsedAt != nil || stored.Revoked {
+		s.revokeFamily(ctx, stored)
+		return nil, ErrRefreshTokenReused
+	}
+	if time.Now().After(stored.ExpiresAt) {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	user, err := s.repo.FindByID(ctx, stored.UserID)
+	if err != nil || user.Disabled || user.PasswordResetRequired {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	nextID, err := newID()
+	if err != nil {
+		return nil, err
+	}
+	claimed, err := s.refreshTokens.MarkUsed(stored.ID, nextID)
+	if err != nil {
+		return nil, err
+	}
+	if !claimed {
+		// Ein paralleler Request hat das Token gerade verbraucht.
+		s.revokeFamily(ctx, stored)
+		return nil, ErrRefreshTokenReused
+	}
+
+	return s.issueTokensWithID(ctx, user, stored.FamilyID, nextID, ip, userAgent)
+}
+
+func (s *AuthService) revokeFamily(ctx context.Context, token *domain.RefreshToken) {
+	log.Printf("Refresh token reuse for user %s, revoking family %s", token.UserID, token.FamilyID)
+	if err := s.refreshTokens.RevokeFamily(token.FamilyID); err != nil {
+		log.Printf("Failed to revoke token family %s: %v", token.FamilyID, err)
+	}
+	if err := s.sessions.Delete(ctx, token.FamilyID); err != nil {
+		log.Printf("Failed to delete session %s: %v", token.FamilyID, err)
 	}
-	return s.repo.Create(user)
 }
 
-func (s *AuthService) Login(email, password string) (string, error) {
-	user, err := s.repo.FindByEmail(email)
+func (s *AuthService) issueTokens(ctx context.Context, user *domain.User, familyID, ip, userAgent string) (*domain.TokenPair, error) {
+	id, err := newID()
+	if err != nil {
+		return nil, err
+	}
+	return s.issueTokensWithID(ctx, user, familyID, id, ip, userAgent)
+}
+
+func (s *AuthService) issueTokensWithID(ctx context.Context, user *domain.User, familyID, refreshID, ip, userAgent string) (*domain.TokenPair, error) {
+	accessToken, jti, err := s.signSessionAccessToken(user, domain.TokenTypeAccess, familyID)
+	if err != nil {
+		return nil, err
+	}
+
+	refreshToken, err := randomToken(32)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	err = s.refreshTokens.Create(&domain.RefreshToken{
+		ID:        refreshID,
+		UserID:    user.ID,
+		FamilyID:  familyID,
+		TokenHash: hashToken(refreshToken),
+		ExpiresAt: now.Add(s.cfg.RefreshExpiry),
+		CreatedAt: now,
+	})
 	if err != nil {
-		return "", errors.New("invalid credentials")
+		return nil, err
 	}
 
-	if bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)) != nil {
-		return "", errors.New("invalid credentials")
+	// Ältere Familien ohne Sitzung erhalten beim nächsten Refresh eine.
+	err = s.sessions.Save(ctx, &domain.Session{
+		ID:              familyID,
+		UserID:          user.ID,
+		UserAgent:       truncateUserAgent(userAgent),
+		IP:              ip,
+		CreatedAt:       now,
+		LastSeenAt:      now,
+		ExpiresAt:       now.Add(s.cfg.RefreshExpiry),
+		AccessJTI:       jti,
+		AccessExpiresAt: now.Add(s.cfg.AccessExpiry),
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+	return &domain.TokenPair{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int(s.cfg.AccessExpiry.Seconds()),
+	}, nil
+}
+
+// JWKS liefert die öffentlichen Schlüssel für /.well-known/jwks.json.
+func (s *AuthService) JWKS() domain.JWKS {
+	return s.keys.JWKS()
+}
+
+// ValidateAccessToken prüft Signatur, Ablauf und Widerruf eines Access-Tokens.
+func (s *AuthService) ValidateAccessToken(tokenStr string) (*domain.Claims, error) {
+	claims := jwt.MapClaims{}
+	token, err := jwt.ParseWithClaims(tokenStr, claims, s.keys.Keyfunc, jwt.WithExpirationRequired())
+	if err != nil || !token.Valid {
+		return nil, ErrInvalidToken
+	}
+
+	userID, _ := claims["user_id"].(string)
+	if userID == "" {
+		return nil, ErrInvalidToken
+	}
+	email, _ := claims["email"].(string)
+	role, _ := claims["role"].(string)
+	jti, _ := claims["jti"].(string)
+	sessionID, _ := claims["sid"].(string)
+	scope, _ := claims["scope"].(string)
+	// Ohne Claim gibt es keine Berechtigungen, auch nicht aus der Rolle
+	permissions, _ := stringSliceClaim(claims["permissions"])
+	exp, _ := claims.GetExpirationTime()
+	issuedAt := issuedAtClaim(claims["iat"])
+	// Tokens von vor Einführung des typ-Claims gelten als normale Access-Tokens
+	tokenType, _ := claims["typ"].(string)
+	if tokenType == "" {
+		tokenType = domain.TokenTypeAccess
+	}
+
+	revoked, err := s.revocations.IsRevoked(jti, userID, issuedAt)
+	if err != nil {
+		log.Printf("Failed to check token revocation for user %s: %v", userID, err)
+		return nil, ErrInvalidToken
+	}
+	if revoked {
+		return nil, ErrInvalidToken
+	}
+
+	return &domain.Claims{
+		ID:          jti,
+		SessionID:   sessionID,
+		Scope:       scope,
+		UserID:      userID,
+		Email:       email,
+		Role:        role,
+		Permissions: permissions,
+		Type:        tokenType,
+		IssuedAt:    issuedAt,
+		ExpiresAt:   exp.Time,
+	}, nil
+}
+
+func (s *AuthService) signAccessToken(user *domain.User, tokenType string) (string, error) {
+	token, _, err := s.signSessionAccessToken(user, tokenType, "")
+	return token, err
+}
+
+// signSessionAccessToken setzt zusätzlich die Sitzung als sid und gibt die
+// jti zurück.
+func (s *AuthService) signSessionAccessToken(user *domain.User, tokenType, sessionID string) (string, string, error) {
+	jti, err := newID()
+	if err != nil {
+		return "", "", err
+	}
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"jti":     jti,
 		"user_id": user.ID,
 		"email":   user.Email,
-		"exp":     time.Now().Add(s.expiry).Unix(),
-	})
+		"role":    user.Role,
+		"typ":     tokenType,
+		"iat":     issuedAtValue(now),
+		"exp":     now.Add(s.cfg.AccessExpiry).Unix(),
+	}
+	// Eingeschränkte Tokens unbestätigter Konten tragen keine Berechtigungen
+	if tokenType != domain.TokenTypeUnverified {
+		if permissions := domain.PermissionsForRole(user.Role); len(permissions) > 0 {
+			claims["permissions"] = permissions
+		}
+	}
+	if sessionID != "" {
+		claims["sid"] = sessionID
+	}
+	token, err := s.keys.Sign(claims)
+	if err != nil {
+		return "", "", err
+	}
+	return token, jti, nil
+}
 
-	return token.SignedString([]byte(s.jwtSecret))
+// stringSliceClaim liest ein JSON-Array aus Strings. ok ist false, wenn der
+// Claim fehlt oder kein solches Array ist.
+func stringSliceClaim(v interface{}) ([]string, bool) {
+	raw, ok := v.([]interface{})
+	if !ok {
+		return nil, false
+	}
+	values := make([]string, 0, len(raw))
+	for _, item := range raw {
+		s, ok := item.(string)
+		if !ok {
+			return nil, false
+		}
+		values = append(values, s)
+	}
+	return values, true
 }
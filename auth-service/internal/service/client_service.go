@@ -0,0 +1,261 @@
+package service
+
+import (
+	"auth-service/internal/domain"
+	"auth-service/internal/ports"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var (
+	ErrInvalidClient     = errors.New("invalid client credentials")
+	ErrInsufficientScope = errors.New("insufficient scope")
+	ErrInvalidScope      = errors.New("invalid scope")
+	ErrClientNotFound    = errors.New("client not found")
+	ErrClientNameMissing = errors.New("client name is required")
+	// ErrInvalidRedirectURI gilt für Clients ohne gültige redirect_uris und
+	// für /authorize-Anfragen mit nicht registrierter redirect_uri.
+	ErrInvalidRedirectURI = errors.New("invalid redirect uri")
+	ErrPublicClientScope  = errors.New("public clients may only use openid, profile and email")
+)
+
+type ClientService struct {
+	clients     ports.ClientRepository
+	keys        *KeySet
+	events      ports.EventPublisher
+	tokenExpiry time.Duration
+}
+
+func NewClientService(clients ports.ClientRepository, keys *KeySet, events ports.EventPublisher, tokenExpiry time.Duration) *ClientService {
+	return &ClientService{clients: clients, keys: keys, events: events, tokenExpiry: tokenExpiry}
+}
+
+// CreateClient legt einen Client an. Das Secret wird nur hier im Klartext
+// zurückgegeben. OIDC-Clients brauchen den Scope openid und mindestens eine
+// redirect_uri; öffentliche Clients erhalten kein Secret.
+func (s *ClientService) CreateClient(actorID, name string, scopes, redirectURIs []string, public bool) (*domain.ClientCredentials, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, ErrClientNameMissing
+	}
+	if len(scopes) == 0 {
+		return nil, ErrInvalidScope
+	}
+	oidc := false
+	for _, scope := range scopes {
+		if !domain.ValidScope(scope) {
+			return nil, ErrInvalidScope
+		}
+		if domain.IsOIDCScope(scope) {
+			oidc = true
+		} else if public {
+			return nil, ErrPublicClientScope
+		}
+	}
+	if oidc || len(redirectURIs) > 0 || public {
+		if !slices.Contains(scopes, domain.ScopeOpenID) {
+			return nil, ErrInvalidScope
+		}
+		if len(redirectURIs) == 0 {
+			return nil, ErrInvalidRedirectURI
+		}
+		for _, uri := range redirectURIs {
+			if !validRedirectURI(uri) {
+				return nil, ErrInvalidRedirectURI
+			}
+		}
+	}
+
+	id, err := newID()
+	if err != nil {
+		return nil, err
+	}
+	client := &domain.Client{
+		ID:           id,
+		Name:         name,
+		Scopes:       scopes,
+		RedirectURIs: redirectURIs,
+		Public:       public,
+		CreatedBy:    actorID,
+		CreatedAt:    time.Now(),
+	}
+	var secret string
+	if !public {
+		if secret, err = randomToken(32); err != nil {
+			return nil, err
+		}
+		client.SecretHash = hashToken(secret)
+	}
+	if err := s.clients.Save(client); err != nil {
+		return nil, err
+	}
+	return &domain.ClientCredentials{Client: client, ClientSecret: secret}, nil
+}
+
+func (s *ClientService) ListClients() ([]domain.Client, error) {
+	return s.clients.FindAll()
+}
+
+// RotateSecret ersetzt das Secret sofort; das alte ist danach ungültig.
+func (s *ClientService) RotateSecret(clientID string) (*domain.ClientCredentials, error) {
+	client, err := s.clients.FindByID(clientID)
+	if err != nil || client.Revoked || client.Public {
+		return nil, ErrClientNotFound
+	}
+	secret, err := randomToken(32)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if err := s.clients.UpdateSecret(client.ID, hashToken(secret), now); err != nil {
+		return nil, err
+	}
+	client.RotatedAt = &now
+	return &domain.ClientCredentials{Client: client, ClientSecret: secret}, nil
+}
+
+// RevokeClient sperrt den Client. Bereits ausgestellte Service-Tokens werden
+// über EventClientRevoked bei den prüfenden Services bis zu ihrem Ablauf
+// gesperrt.
+func (s *ClientService) RevokeClient(actorID, clientID string) error {
+	if err := s.clients.Revoke(clientID); err != nil {
+		return ErrClientNotFound
+	}
+	now := time.Now()
+	publishAsync(s.events, &domain.AuthEvent{
+		EventType: domain.EventClientRevoked,
+		ActorID:   actorID,
+		Data: map[string]interface{}{
+			"client_id":  clientID,
+			"expires_at": now.Add(s.tokenExpiry),
+		},
+		Timestamp: now,
+	})
+	return nil
+}
+
+// IssueServiceToken stellt für den client_credentials Grant ein Token mit den
+// angefragten Scopes aus. Ohne Angabe erhält der Client alle seine Scopes
+// außer den OIDC-Scopes, die nur im Namen eines Users gelten.
+func (s *ClientService) IssueServiceToken(clientID, secret, scope string) (*domain.ServiceToken, error) {
+	client, err := s.AuthenticateClient(clientID, secret, "")
+	if err != nil {
+		return nil, err
+	}
+
+	scopes := strings.Fields(scope)
+	if len(scopes) == 0 {
+		for _, sc := range client.Scopes {
+			if !domain.IsOIDCScope(sc) {
+				scopes = append(scopes, sc)
+			}
+		}
+		if len(scopes) == 0 {
+			return nil, ErrInvalidScope
+		}
+	}
+	for _, sc := range scopes {
+		if !client.HasScope(sc) || domain.IsOIDCScope(sc) {
+			return nil, ErrInvalidScope
+		}
+	}
+
+	jti, err := newID()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	granted := strings.Join(scopes, " ")
+	token, err := s.keys.Sign(jwt.MapClaims{
+		"jti":       jti,
+		"sub":       client.ID,
+		"client_id": client.ID,
+		"scope":     granted,
+		"typ":       domain.TokenTypeService,
+		"iat":       now.Unix(),
+		"exp":       now.Add(s.tokenExpiry).Unix(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &domain.ServiceToken{
+		AccessToken: token,
+		TokenType:   "Bearer",
+		ExpiresIn:   int(s.tokenExpiry.Seconds()),
+		Scope:       granted,
+	}, nil
+}
+
+// AuthenticateClient prüft client_id und Secret und ob der Client scope hat.
+func (s *ClientService) AuthenticateClient(clientID, secret, scope string) (*domain.Client, error) {
+	if clientID == "" || secret == "" {
+		return nil, ErrInvalidClient
+	}
+	client, err := s.clients.FindByID(clientID)
+	if err != nil || client.Revoked || client.Public {
+		return nil, ErrInvalidClient
+	}
+	if subtle.ConstantTimeCompare([]byte(client.SecretHash), []byte(hashToken(secret))) != 1 {
+		return nil, ErrInvalidClient
+	}
+	if scope != "" && !client.HasScope(scope) {
+		return nil, ErrInsufficientScope
+	}
+	return client, nil
+}
+
+// SeedIntrospectionClients legt Clients aus der Konfiguration an
+// ("id:secret,id2:secret2"). Vorhandene Clients bleiben unverändert, damit
+// ein Widerruf oder geänderte Scopes den Neustart überstehen; ein neues
+// Secret wird über /admin/clients/:id/rotate gesetzt.
+func (s *ClientService) SeedIntrospectionClients(spec string) error {
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		id, secret, ok := strings.Cut(entry, ":")
+		if !ok || id == "" || secret == "" {
+			return fmt.Errorf("invalid client entry %q, expected id:secret", entry)
+		}
+		created, err := s.clients.CreateIfMissing(&domain.Client{
+			ID:         id,
+			Name:       id,
+			SecretHash: hashToken(secret),
+			Scopes:     []string{domain.ScopeIntrospect},
+			CreatedAt:  time.Now(),
+		})
+		if err != nil {
+			return err
+		}
+		if created {
+			log.Printf("Seeded introspection client %s", id)
+		}
+	}
+	return nil
+}
+
+// validRedirectURI verlangt eine absolute URL ohne Fragment. http ist nur für
+// localhost erlaubt.
+func validRedirectURI(raw string) bool {
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" || u.Fragment != "" {
+		return false
+	}
+	switch u.Scheme {
+	case "https":
+		return true
+	case "http":
+		host := u.Hostname()
+		return host == "localhost" || host == "127.0.0.1" || host == "::1"
+	}
+	return false
+}
@@ -0,0 +1,272 @@
+package service
+
+import (
+	"archive/zip"
+	"auth-service/internal/domain"
+	"auth-service/internal/ports"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+)
+
+// DataRequestServiceName ist der Eintrag des auth-service in DataRequest.Services.
+const DataRequestServiceName = "auth-service"
+
+var (
+	ErrInvalidDataRequest  = errors.New("invalid data request")
+	ErrDataRequestNotFound = errors.New("data request not found")
+	ErrDataRequestNotReady = errors.New("data request is not a completed export")
+)
+
+type DataRequestConfig struct {
+	// Services, die auf user_export_requested bzw. user_erasure_requested
+	// antworten müssen, bevor eine Anfrage abgeschlossen ist.
+	Services []string
+	// Retention begrenzt, wie lange Anfrage und Exportdaten gespeichert bleiben.
+	Retention time.Duration
+}
+
+// DataRequestService bearbeitet Auskunfts- und Löschanfragen nach DSGVO. Die
+// anderen Services werden über auth-events beauftragt und melden ihr Ergebnis
+// über HandleResult zurück.
+type DataRequestService struct {
+	requests ports.DataRequestRepository
+	users    ports.UserRepository
+	audit    ports.AuditRepository
+	auth     *AuthService
+	cfg      DataRequestConfig
+}
+
+func NewDataRequestService(requests ports.DataRequestRepository, users ports.UserRepository, audit ports.AuditRepository, auth *AuthService, cfg DataRequestConfig) *DataRequestService {
+	return &DataRequestService{requests: requests, users: users, audit: audit, auth: auth, cfg: cfg}
+}
+
+// Start legt eine Anfrage an und beauftragt die anderen Services. Bei einer
+// Löschung wird das Konto danach sofort entfernt; die Anfrage bleibt bis zum
+// Ablauf der Aufbewahrungsfrist abrufbar.
+func (s *DataRequestService) Start(ctx context.Context, actorID, userID, requestType, ip string) (*domain.DataRequest, error) {
+	eventType := domain.EventUserExportRequested
+	switch requestType {
+	case domain.DataRequestExport:
+	case domain.DataRequestErasure:
+		if actorID == userID {
+			return nil, ErrSelfAccountChange
+		}
+		eventType = domain.EventUserErasureRequested
+	default:
+		return nil, ErrInvalidDataRequest
+	}
+
+	user, err := s.users.FindByID(ctx, userID)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+	id, err := newID()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	request := &domain.DataRequest{
+		ID:        id,
+		Type:      requestType,
+		UserID:    user.ID,
+		ActorID:   actorID,
+		Status:    domain.DataRequestPending,
+		Services:  map[string]domain.DataRequestServiceStatus{},
+		CreatedAt: now,
+		ExpiresAt: now.Add(s.cfg.Retention),
+	}
+	for _, name := range append([]string{DataRequestServiceName}, s.cfg.Services...) {
+		request.Services[name] = domain.DataRequestServiceStatus{Status: domain.DataRequestPending}
+	}
+	if err := s.requests.Create(ctx, request); err != nil {
+		return nil, err
+	}
+
+	// Eigene Daten zuerst exportieren, solange das Konto sicher noch existiert
+	result := &domain.DataRequestResult{RequestID: id, Service: DataRequestServiceName, Type: requestType}
+	if requestType == domain.DataRequestExport {
+		result.Data, err = s.exportOwnData(ctx, user)
+	}
+
+	// Ohne das Event würde die Anfrage nie fertig, deshalb nicht asynchron
+	event := &domain.AuthEvent{
+		EventType: eventType,
+		UserID:    user.ID,
+		ActorID:   actorID,
+		IP:        ip,
+		Data:      map[string]interface{}{"request_id": id},
+		Timestamp: now,
+	}
+	if pubErr := s.auth.events.Publish(ctx, event); pubErr != nil {
+		if err := s.requests.Finish(ctx, id, domain.DataRequestFailed); err != nil {
+			log.Printf("Failed to mark data request %s as failed: %v", id, err)
+		}
+		return nil, pubErr
+	}
+
+	if requestType == domain.DataRequestErasure {
+		err = s.eraseOwnData(ctx, user, actorID, ip)
+	}
+	result.Status = domain.DataRequestCompleted
+	if err != nil {
+		log.Printf("Data request %s failed in %s: %v", id, DataRequestServiceName, err)
+		result.Status = domain.DataRequestFailed
+		result.Error = err.Error()
+	}
+	if err := s.HandleResult(ctx, result); err != nil {
+		return nil, err
+	}
+	log.Printf("Data request %s (%s) for user %s started by %s", id, requestType, user.ID, actorID)
+	return s.Get(ctx, id)
+}
+
+func (s *DataRequestService) Get(ctx context.Context, id string) (*domain.DataRequest, error) {
+	request, err := s.requests.FindByID(ctx, id)
+	if err != nil {
+		return nil, ErrDataRequestNotFound
+	}
+	return request, nil
+}
+
+// HandleResult übernimmt die Rückmeldung eines Services. Die Anfrage ist
+// abgeschlossen, sobald alle Services fertig sind, und fehlgeschlagen, sobald
+// einer einen Fehler meldet.
+func (s *DataRequestService) HandleResult(ctx context.Context, result *domain.DataRequestResult) error {
+	request, err := s.requests.FindByID(ctx, result.RequestID)
+	if err != nil {
+		return ErrDataRequestNotFound
+	}
+	if _, ok := request.Services[result.Service]; !ok {
+		return fmt.Errorf("%w: unexpected service %q", ErrInvalidDataRequest, result.Service)
+	}
+	if result.Status != domain.DataRequestCompleted && result.Status != domain.DataRequestFailed {
+		return fmt.Errorf("%w: unknown status %q", ErrInvalidDataRequest, result.Status)
+	}
+
+	if request.Type == domain.DataRequestExport && result.Status == domain.DataRequestCompleted {
+		part := &domain.DataExportPart{
+			RequestID: request.ID,
+			Service:   result.Service,
+			Data:      result.Data,
+			ExpiresAt: request.ExpiresAt,
+		}
+		if err := s.requests.SaveExportPart(ctx, part); err != nil {
+			return err
+		}
+	}
+
+	now := time.Now()
+	request, err = s.requests.SetServiceStatus(ctx, request.ID, result.Service, domain.DataRequestServiceStatus{
+		Status:      result.Status,
+		Error:       result.Error,
+		CompletedAt: &now,
+	})
+	if err != nil {
+		return err
+	}
+	if status := overallStatus(request.Services); status != domain.DataRequestPending {
+		return s.requests.Finish(ctx, request.ID, status)
+	}
+	return nil
+}
+
+// ExportArchive liefert den Export als ZIP mit einer JSON-Datei pro Service.
+func (s *DataRequestService) ExportArchive(ctx context.Context, id string) ([]byte, error) {
+	request, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if request.Type != domain.DataRequestExport || request.Status != domain.DataRequestCompleted {
+		return nil, ErrDataRequestNotReady
+	}
+	parts, err := s.requests.FindExportParts(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	for _, part := range parts {
+		f, err := archive.Create(part.Service + ".json")
+		if err != nil {
+			return nil, err
+		}
+		var pretty bytes.Buffer
+		if err := json.Indent(&pretty, part.Data, "", "  "); err != nil {
+			pretty.Reset()
+			pretty.Write(part.Data)
+		}
+		if _, err := f.Write(pretty.Bytes()); err != nil {
+			return nil, err
+		}
+	}
+	if err := archive.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// exportOwnData sammelt Konto, Sitzungen und Audit-Log des Users.
+func (s *DataRequestService) exportOwnData(ctx context.Context, user *domain.User) ([]byte, error) {
+	sessions, err := s.auth.sessions.FindByUser(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	var events []domain.AuthEvent
+	filter := domain.AuditFilter{UserID: user.ID, Page: 1, PageSize: maxAuditPageSize}
+	for {
+		page, total, err := s.audit.Find(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, page...)
+		if len(page) == 0 || int64(len(events)) >= total {
+			break
+		}
+		filter.Page++
+	}
+	return json.Marshal(struct {
+		User     *domain.User       `json:"user"`
+		Sessions []domain.Session   `json:"sessions"`
+		AuditLog []domain.AuthEvent `json:"audit_log"`
+	}{user, sessions, events})
+}
+
+// eraseOwnData löscht das Konto und entfernt E-Mail und IP aus dem Audit-Log.
+// Die Events selbst bleiben als Sicherheitsprotokoll erhalten.
+func (s *DataRequestService) eraseOwnData(ctx context.Context, user *domain.User, actorID, ip string) error {
+	if err := s.auth.RevokeAllTokens(ctx, user.ID, actorID, ip); err != nil {
+		return err
+	}
+	if err := s.users.Delete(ctx, user.ID); err != nil {
+		return err
+	}
+	if err := s.audit.AnonymizeUser(ctx, user.ID); err != nil {
+		return err
+	}
+	s.auth.emit(&domain.AuthEvent{
+		EventType: domain.EventUserDeleted,
+		UserID:    user.ID,
+		ActorID:   actorID,
+		IP:        ip,
+	})
+	return nil
+}
+
+func overallStatus(services map[string]domain.DataRequestServiceStatus) string {
+	status := domain.DataRequestCompleted
+	for _, s := range services {
+		switch s.Status {
+		case domain.DataRequestFailed:
+			return domain.DataRequestFailed
+		case domain.DataRequestPending:
+			status = domain.DataRequestPending
+		}
+	}
+	return status
+}
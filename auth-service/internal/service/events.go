@@ -0,0 +1,21 @@
+package service
+
+import (
+	"auth-service/internal/domain"
+	"auth-service/internal/ports"
+	"context"
+	"time"
+)
+
+// publishAsync veröffentlicht ein Event im Hintergrund, damit ein langsamer
+// oder ausgefallener Broker keine Requests blockiert. Fehler loggt der Publisher.
+func publishAsync(publisher ports.EventPublisher, event *domain.AuthEvent) {
+	if event.Timestamp.IsZero() {
+		event.Timestamp = time.Now()
+	}
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = publisher.Publish(ctx, event)
+	}()
+}
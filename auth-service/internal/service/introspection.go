@@ -0,0 +1,31 @@
+package service
+
+import "auth-service/internal/domain"
+
+// Introspect prüft ein Token für andere Services. Nur normale und
+// eingeschränkte Access-Tokens gelten als aktiv, mfa_pending Tokens nicht.
+func (s *AuthService) Introspect(token string) *domain.Introspection {
+	claims, err := s.ValidateAccessToken(token)
+	if err != nil {
+		return &domain.Introspection{Active: false}
+	}
+	if claims.Type != domain.TokenTypeAccess && claims.Type != domain.TokenTypeUnverified {
+		return &domain.Introspection{Active: false}
+	}
+
+	result := &domain.Introspection{
+		Active:      true,
+		Subject:     claims.UserID,
+		UserID:      claims.UserID,
+		Email:       claims.Email,
+		Role:        claims.Role,
+		Permissions: claims.Permissions,
+		Type:        claims.Type,
+		JTI:         claims.ID,
+		ExpiresAt:   claims.ExpiresAt.Unix(),
+	}
+	if !claims.IssuedAt.IsZero() {
+		result.IssuedAt = claims.IssuedAt.Unix()
+	}
+	return result
+}
@@ -0,0 +1,231 @@
+package service
+
+import (
+	"auth-service/internal/domain"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type signingKey struct {
+	id      string
+	method  jwt.SigningMethod
+	private interface{}
+	public  interface{}
+}
+
+// KeySet hält den aktiven Signierschlüssel und alle Schlüssel, deren
+// öffentliche Hälfte noch veröffentlicht wird. So bleiben Tokens, die vor einer
+// Rotation ausgestellt wurden, bis zu ihrem Ablauf gültig.
+type KeySet struct {
+	active *signingKey
+	keys   map[string]*signingKey
+}
+
+// NewHMACKeySet erzeugt ein KeySet mit gemeinsamem Secret (HS256). Es wird
+// kein öffentlicher Schlüssel veröffentlicht.
+func NewHMACKeySet(secret string) (*KeySet, error) {
+	if secret == "" {
+		return nil, errors.New("JWT_SECRET not configured")
+	}
+	key := &signingKey{method: jwt.SigningMethodHS256, private: []byte(secret), public: []byte(secret)}
+	return &KeySet{active: key, keys: map[string]*signingKey{"": key}}, nil
+}
+
+// LoadKeySet lädt alle PEM-Dateien (PKCS#8 oder PKCS#1) aus dir. Der Dateiname
+// ohne Endung ist die Key-ID. Aktiv ist activeKID bzw. ohne Angabe der
+// alphabetisch letzte Schlüssel; zum Rotieren legt man also eine neue Datei an
+// und entfernt die alte erst, wenn alle damit signierten Tokens abgelaufen sind.
+// Ist dir leer, wird ein Schlüssel für alg erzeugt und dort abgelegt, damit er
+// einen Neustart übersteht; nur ohne dir bleibt er flüchtig.
+func LoadKeySet(alg, dir, activeKID string) (*KeySet, error) {
+	set := &KeySet{keys: map[string]*signingKey{}}
+
+	if dir != "" {
+		files, err := filepath.Glob(filepath.Join(dir, "*.pem"))
+		if err != nil {
+			return nil, err
+		}
+		sort.Strings(files)
+		for _, f := range files {
+			key, err := loadPEMKey(f)
+			if err != nil {
+				return nil, fmt.Errorf("load key %s: %w", f, err)
+			}
+			set.keys[key.id] = key
+			set.active = key
+		}
+	}
+
+	if len(set.keys) == 0 {
+		key, err := generateKey(alg)
+		if err != nil {
+			return nil, err
+		}
+		if dir == "" {
+			log.Printf("No signing keys found, generated ephemeral %s key %s", alg, key.id)
+		} else {
+			path, err := savePEMKey(dir, key)
+			if err != nil {
+				return nil, fmt.Errorf("store generated key: %w", err)
+			}
+			log.Printf("No signing keys found, generated %s key %s", alg, path)
+		}
+		set.keys[key.id] = key
+		set.active = key
+	}
+
+	if activeKID != "" {
+		key, ok := set.keys[activeKID]
+		if !ok {
+			return nil, fmt.Errorf("active key %s not found", activeKID)
+		}
+		set.active = key
+	}
+	return set, nil
+}
+
+func loadPEMKey(path string) (*signingKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+
+	var parsed interface{}
+	if parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes); err != nil {
+		if parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes); err != nil {
+			return nil, errors.New("unsupported private key format")
+		}
+	}
+
+	kid := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	return newSigningKey(kid, parsed)
+}
+
+// savePEMKey schreibt den privaten Schlüssel als PKCS#8 nach dir/<kid>.pem.
+func savePEMKey(dir string, key *signingKey) (string, error) {
+	der, err := x509.MarshalPKCS8PrivateKey(key.private)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, key.id+".pem")
+	data := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+func generateKey(alg string) (*signingKey, error) {
+	kid, err := newID()
+	if err != nil {
+		return nil, err
+	}
+	switch alg {
+	case "RS256":
+		priv, err := rsa.GenerateKey(rand.Reader, 2048)
+		if err != nil {
+			return nil, err
+		}
+		return newSigningKey(kid, priv)
+	case "EdDSA":
+		_, priv, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, err
+		}
+		return newSigningKey(kid, priv)
+	default:
+		return nil, fmt.Errorf("unsupported signing algorithm %q", alg)
+	}
+}
+
+func newSigningKey(kid string, priv interface{}) (*signingKey, error) {
+	switch k := priv.(type) {
+	case *rsa.PrivateKey:
+		return &signingKey{id: kid, method: jwt.SigningMethodRS256, private: k, public: &k.PublicKey}, nil
+	case ed25519.PrivateKey:
+		return &signingKey{id: kid, method: jwt.SigningMethodEdDSA, private: k, public: k.Public()}, nil
+	default:
+		return nil, errors.New("unsupported key type, use RSA or Ed25519")
+	}
+}
+
+// Sign signiert die Claims mit dem aktiven Schlüssel und setzt die Key-ID im Header.
+func (k *KeySet) Sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(k.active.method, claims)
+	if k.active.id != "" {
+		token.Header["kid"] = k.active.id
+	}
+	return token.SignedString(k.active.private)
+}
+
+// Algorithm ist der Algorithmus des aktiven Schlüssels, z.B. RS256.
+func (k *KeySet) Algorithm() string {
+	return k.active.method.Alg()
+}
+
+// Asymmetric ist false bei HS256; dann kann niemand außer uns Signaturen
+// prüfen, was etwa für ID-Tokens nicht reicht.
+func (k *KeySet) Asymmetric() bool {
+	_, hmac := k.active.private.([]byte)
+	return !hmac
+}
+
+// Keyfunc liefert den passenden Verifikationsschlüssel für jwt.Parse.
+func (k *KeySet) Keyfunc(t *jwt.Token) (interface{}, error) {
+	kid, _ := t.Header["kid"].(string)
+	key, ok := k.keys[kid]
+	if !ok || t.Method.Alg() != key.method.Alg() {
+		return nil, jwt.ErrTokenUnverifiable
+	}
+	return key.public, nil
+}
+
+// JWKS gibt die öffentlichen Schlüssel aller bekannten Key-IDs zurück.
+func (k *KeySet) JWKS() domain.JWKS {
+	jwks := domain.JWKS{Keys: []domain.JWK{}}
+	for _, key := range k.keys {
+		switch pub := key.public.(type) {
+		case *rsa.PublicKey:
+			jwks.Keys = append(jwks.Keys, domain.JWK{
+				Kty: "RSA",
+				Kid: key.id,
+				Use: "sig",
+				Alg: key.method.Alg(),
+				N:   base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+				E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+			})
+		case ed25519.PublicKey:
+			jwks.Keys = append(jwks.Keys, domain.JWK{
+				Kty: "OKP",
+				Kid: key.id,
+				Use: "sig",
+				Alg: key.method.Alg(),
+				Crv: "Ed25519",
+				X:   base64.RawURLEncoding.EncodeToString(pub),
+			})
+		}
+	}
+	sort.Slice(jwks.Keys, func(i, j int) bool { return jwks.Keys[i].Kid < jwks.Keys[j].Kid })
+	return jwks
+}
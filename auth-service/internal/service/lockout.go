@@ -0,0 +1,184 @@
+package service
+
+import (
+	"auth-service/internal/domain"
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+)
+
+// LockoutConfig steuert den Schutz gegen Brute-Force-Logins.
+type LockoutConfig struct {
+	// MaxFailures fehlgeschlagene Logins in Folge sperren das Konto für
+	// LockoutDuration.
+	MaxFailures     int
+	LockoutDuration time.Duration
+	// Nach dem n-ten Fehlversuch muss BackoffBase * 2^(n-1) gewartet werden.
+	BackoffBase time.Duration
+	// MaxFailuresPerIP Fehlversuche innerhalb von IPWindow sperren die IP für
+	// den Rest des Fensters, unabhängig vom Konto.
+	MaxFailuresPerIP int
+	IPWindow         time.Duration
+}
+
+// LoginBlockedError wird zurückgegeben, solange ein Login wegen vorheriger
+// Fehlversuche nicht erlaubt ist.
+type LoginBlockedError struct {
+	Locked     bool
+	RetryAfter time.Duration
+}
+
+func (e *LoginBlockedError) Error() string {
+	if e.Locked {
+		return fmt.Sprintf("account locked, retry in %ds", int(e.RetryAfter.Seconds())+1)
+	}
+	return fmt.Sprintf("too many failed attempts, retry in %ds", int(e.RetryAfter.Seconds())+1)
+}
+
+// beginLoginAttempt prüft Sperre und progressive Wartezeit vor der
+// Passwort- bzw. Codeprüfung und zählt den Versuch vorab als Fehlversuch.
+// Der Zähler wird nur erhöht, wenn er seit dem Lesen des Users unverändert
+// ist; parallele Versuche mit demselben Stand werden so abgelehnt, statt die
+// Wartezeit gemeinsam zu umgehen. Zurückgegeben wird der neue Zählerstand.
+func (s *AuthService) beginLoginAttempt(ctx context.Context, user *domain.User) (int, error) {
+	now := time.Now()
+	if user.LockedUntil != nil && now.Before(*user.LockedUntil) {
+		return 0, &LoginBlockedError{Locked: true, RetryAfter: user.LockedUntil.Sub(now)}
+	}
+	if user.FailedLogins > 0 && user.LastFailedLogin != nil {
+		next := user.LastFailedLogin.Add(s.backoff(user.FailedLogins))
+		if now.Before(next) {
+			return 0, &LoginBlockedError{RetryAfter: next.Sub(now)}
+		}
+	}
+
+	failures, ok, err := s.repo.BeginLoginAttempt(ctx, user.ID, user.FailedLogins)
+	if err != nil {
+		return 0, err
+	}
+	if !ok {
+		return 0, &LoginBlockedError{RetryAfter: s.backoff(user.FailedLogins + 1)}
+	}
+	return failures, nil
+}
+
+func (s *AuthService) backoff(failures int) time.Duration {
+	shift := failures - 1
+	if shift > 10 {
+		shift = 10
+	}
+	return s.cfg.Lockout.BackoffBase << uint(shift)
+}
+
+// lockIfExceeded sperrt das Konto, wenn der von beginLoginAttempt gezählte
+// Fehlversuch das Limit erreicht.
+func (s *AuthService) lockIfExceeded(ctx context.Context, user *domain.User, failures int, ip string) {
+	if failures < s.cfg.Lockout.MaxFailures {
+		return
+	}
+
+	until := time.Now().Add(s.cfg.Lockout.LockoutDuration)
+	if err := s.repo.LockUntil(ctx, user.ID, until); err != nil {
+		log.Printf("Failed to lock user %s: %v", user.ID, err)
+		return
+	}
+	log.Printf("User %s locked until %s after %d failed logins", user.ID, until.Format(time.RFC3339), failures)
+	s.emit(&domain.AuthEvent{
+		EventType: domain.EventUserLocked,
+		UserID:    user.ID,
+		Email:     user.Email,
+		IP:        ip,
+		Data: map[string]interface{}{
+			"failed_logins": failures,
+			"locked_until":  until,
+		},
+	})
+}
+
+// resetFailedLogins nimmt den vorab gezählten Versuch nach erfolgreicher
+// Prüfung zurück.
+func (s *AuthService) resetFailedLogins(ctx context.Context, user *domain.User) {
+	if err := s.repo.ResetFailedLogins(ctx, user.ID); err != nil {
+		log.Printf("Failed to reset failed logins for user %s: %v", user.ID, err)
+		return
+	}
+	user.FailedLogins = 0
+	user.LastFailedLogin = nil
+	user.LockedUntil = nil
+}
+
+// UnlockUser hebt eine Sperre durch einen Admin auf.
+func (s *AuthService) UnlockUser(ctx context.Context, userID string) error {
+	if err := s.repo.ResetFailedLogins(ctx, userID); err != nil {
+		return ErrUserNotFound
+	}
+	return nil
+}
+
+// ipThrottle zählt Fehlversuche pro IP im Speicher. Bei mehreren Instanzen
+// gilt das Limit pro Instanz; die Sperre pro Konto liegt dagegen in der DB.
+// Einmal pro Fenster werden alle abgelaufenen Einträge entfernt, damit die Map
+// bei wechselnden Absender-IPs nicht unbegrenzt wächst.
+type ipThrottle struct {
+	mu        sync.Mutex
+	max       int
+	window    time.Duration
+	failures  map[string][]time.Time
+	lastSweep time.Time
+}
+
+func newIPThrottle(max int, window time.Duration) *ipThrottle {
+	return &ipThrottle{max: max, window: window, failures: map[string][]time.Time{}}
+}
+
+func (t *ipThrottle) check(ip string) error {
+	if t.max <= 0 || ip == "" {
+		return nil
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	recent := t.prune(ip, time.Now())
+	if len(recent) >= t.max {
+		return &LoginBlockedError{RetryAfter: recent[0].Add(t.window).Sub(time.Now())}
+	}
+	return nil
+}
+
+func (t *ipThrottle) fail(ip string) {
+	if t.max <= 0 || ip == "" {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(t.lastSweep) >= t.window {
+		t.sweep(now)
+	}
+	t.failures[ip] = append(t.prune(ip, now), now)
+}
+
+func (t *ipThrottle) sweep(now time.Time) {
+	for ip := range t.failures {
+		t.prune(ip, now)
+	}
+	t.lastSweep = now
+}
+
+func (t *ipThrottle) prune(ip string, now time.Time) []time.Time {
+	recent := t.failures[ip][:0]
+	for _, at := range t.failures[ip] {
+		if now.Sub(at) < t.window {
+			recent = append(recent, at)
+		}
+	}
+	if len(recent) == 0 {
+		delete(t.failures, ip)
+		return nil
+	}
+	t.failures[ip] = recent
+	return recent
+}
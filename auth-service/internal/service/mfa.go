@@ -0,0 +1,199 @@
+package service
+
+import (
+	"auth-service/internal/domain"
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var (
+	ErrInvalidMFACode        = errors.New("invalid verification code")
+	ErrMFANotPending         = errors.New("no two-factor enrollment in progress")
+	ErrMFAAlreadyEnabled     = errors.New("two-factor authentication already enabled")
+	ErrMFAEnrollmentRequired = errors.New("two-factor enrollment required")
+	ErrMFAMandatory          = errors.New("two-factor authentication is mandatory for admins")
+)
+
+const recoveryCodeCount = 10
+
+// startMFA stellt nach korrektem Passwort ein kurzlebiges mfa_pending Token aus.
+func (s *AuthService) startMFA(user *domain.User) (*domain.TokenPair, error) {
+	now := time.Now()
+	token, err := s.keys.Sign(jwt.MapClaims{
+		"user_id": user.ID,
+		"typ":     domain.TokenTypeMFAPending,
+		"iat":     issuedAtValue(now),
+		"exp":     now.Add(s.cfg.MFAPendingExpiry).Unix(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &domain.TokenPair{
+		ExpiresIn:             int(s.cfg.MFAPendingExpiry.Seconds()),
+		MFARequired:           true,
+		MFAToken:              token,
+		MFAEnrollmentRequired: !user.MFAEnabled,
+	}, nil
+}
+
+// EnrollTOTP erzeugt ein neues Secret. Aktiv wird es erst mit ActivateTOTP,
+// damit sich niemand durch eine abgebrochene Einrichtung aussperrt.
+func (s *AuthService) EnrollTOTP(ctx context.Context, claims *domain.Claims) (*domain.MFAEnrollment, error) {
+	user, err := s.repo.FindByID(ctx, claims.UserID)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+	if user.MFAEnabled {
+		return nil, ErrMFAAlreadyEnabled
+	}
+
+	secret, err := generateTOTPSecret()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.SetPendingTOTPSecret(ctx, user.ID, secret); err != nil {
+		return nil, err
+	}
+	return &domain.MFAEnrollment{
+		Secret:          secret,
+		ProvisioningURI: totpProvisioningURI(s.cfg.MFAIssuer, user.Email, secret),
+	}, nil
+}
+
+// ActivateTOTP bestätigt die Einrichtung mit einem ersten gültigen Code. Kommt
+// der Aufruf aus dem Login-Flow (mfa_pending), wird der Login abgeschlossen.
+func (s *AuthService) ActivateTOTP(ctx context.Context, claims *domain.Claims, code, ip, userAgent string) (*domain.MFAActivation, error) {
+	user, err := s.repo.FindByID(ctx, claims.UserID)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+	if user.MFAEnabled {
+		return nil, ErrMFAAlreadyEnabled
+	}
+	if user.PendingTOTPSecret == "" {
+		return nil, ErrMFANotPending
+	}
+
+	counter, ok := validateTOTP(user.PendingTOTPSecret, code, time.Now())
+	if !ok {
+		return nil, ErrInvalidMFACode
+	}
+
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.EnableTOTP(ctx, user.ID, user.PendingTOTPSecret, hashes); err != nil {
+		return nil, err
+	}
+	if _, err := s.repo.AdvanceTOTPCounter(ctx, user.ID, counter); err != nil {
+		return nil, err
+	}
+
+	activation := &domain.MFAActivation{RecoveryCodes: codes}
+	if claims.Type == domain.TokenTypeMFAPending {
+		user.MFAEnabled = true
+		if activation.Tokens, err = s.completeLogin(ctx, user, ip, userAgent); err != nil {
+			return nil, err
+		}
+		s.loggedIn(user, activation.Tokens, ip, "mfa")
+	}
+	return activation, nil
+}
+
+// VerifyMFA ist die zweite Login-Stufe: mfa_pending Token plus TOTP- oder
+// Recovery-Code ergeben das eigentliche Token-Paar. Falsche Codes zählen wie
+// falsche Passwörter für die Kontosperre.
+func (s *AuthService) VerifyMFA(ctx context.Context, mfaToken, code, ip, userAgent string) (*domain.TokenPair, error) {
+	claims, err := s.ValidateAccessToken(mfaToken)
+	if err != nil || claims.Type != domain.TokenTypeMFAPending {
+		return nil, ErrInvalidToken
+	}
+
+	user, err := s.repo.FindByID(ctx, claims.UserID)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	if !user.MFAEnabled {
+		return nil, ErrMFAEnrollmentRequired
+	}
+	failures, err := s.beginLoginAttempt(ctx, user)
+	if err != nil {
+		s.loginFailed(user, ip, "blocked")
+		return nil, err
+	}
+
+	ok, err := s.checkMFACode(ctx, user, code)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		s.loginFailed(user, ip, "invalid_mfa_code")
+		s.lockIfExceeded(ctx, user, failures, ip)
+		return nil, ErrInvalidMFACode
+	}
+	s.resetFailedLogins(ctx, user)
+
+	pair, err := s.completeLogin(ctx, user, ip, userAgent)
+	if err != nil {
+		return nil, err
+	}
+	s.loggedIn(user, pair, ip, "mfa")
+	return pair, nil
+}
+
+// DisableTOTP ist nur für Nicht-Admins erlaubt und verlangt einen gültigen Code.
+func (s *AuthService) DisableTOTP(ctx context.Context, claims *domain.Claims, code string) error {
+	user, err := s.repo.FindByID(ctx, claims.UserID)
+	if err != nil {
+		return ErrUserNotFound
+	}
+	if user.Role == domain.RoleAdmin {
+		return ErrMFAMandatory
+	}
+	if !user.MFAEnabled {
+		return nil
+	}
+
+	ok, err := s.checkMFACode(ctx, user, code)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrInvalidMFACode
+	}
+	return s.repo.DisableTOTP(ctx, user.ID)
+}
+
+func (s *AuthService) checkMFACode(ctx context.Context, user *domain.User, code string) (bool, error) {
+	if counter, ok := validateTOTP(user.TOTPSecret, code, time.Now()); ok {
+		// Jeder Code darf nur einmal verwendet werden
+		return s.repo.AdvanceTOTPCounter(ctx, user.ID, counter)
+	}
+	return s.repo.UseRecoveryCode(ctx, user.ID, hashToken(normalizeRecoveryCode(code)))
+}
+
+// generateRecoveryCodes liefert die Codes im Klartext und ihre Hashes.
+func generateRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, 0, recoveryCodeCount)
+	hashes := make([]string, 0, recoveryCodeCount)
+	for i := 0; i < recoveryCodeCount; i++ {
+		secret, err := generateTOTPSecret()
+		if err != nil {
+			return nil, nil, err
+		}
+		raw := strings.ToLower(secret[:10])
+		codes = append(codes, raw[:5]+"-"+raw[5:])
+		hashes = append(hashes, hashToken(raw))
+	}
+	return codes, hashes, nil
+}
+
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	return strings.ReplaceAll(code, "-", "")
+}
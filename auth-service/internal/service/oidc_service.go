@@ -0,0 +1,416 @@
+package service
+
+import (
+	"auth-service/internal/domain"
+	"auth-service/internal/ports"
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var (
+	// ErrUnknownOIDCClient und ErrInvalidRedirectURI dürfen nicht an den Client
+	// zurückgeleitet werden, da dessen redirect_uri nicht vertrauenswürdig ist.
+	ErrUnknownOIDCClient = errors.New("unknown client")
+	ErrMFACodeRequired   = errors.New("two-factor code required")
+)
+
+// OAuthError ist ein Fehler nach RFC 6749 (error, error_description), der an
+// den Client weitergegeben wird.
+type OAuthError struct {
+	Code        string
+	Description string
+}
+
+func (e *OAuthError) Error() string {
+	if e.Description == "" {
+		return e.Code
+	}
+	return e.Code + ": " + e.Description
+}
+
+const (
+	consentTicketType   = "oidc_consent"
+	consentTicketExpiry = 5 * time.Minute
+)
+
+type OIDCConfig struct {
+	// Issuer ist die öffentliche Basis-URL des auth-service, z.B.
+	// https://auth.example.com. Alle Endpunkte im Discovery-Dokument hängen daran.
+	Issuer     string
+	CodeExpiry time.Duration
+}
+
+// AuthorizeResult ist entweder eine fertige Weiterleitung zum Client oder die
+// Aufforderung, den User um Zustimmung zu bitten.
+type AuthorizeResult struct {
+	RedirectURL   string
+	ConsentTicket string
+	Client        *domain.Client
+	Scopes        []string
+}
+
+// OIDCService macht den auth-service zum OpenID Provider (Authorization-Code-
+// Flow mit PKCE). Die Anmeldung läuft über dieselbe Prüfung wie /login.
+type OIDCService struct {
+	auth     *AuthService
+	clients  ports.ClientRepository
+	consents ports.ConsentRepository
+	codes    ports.AuthorizationCodeRepository
+	cfg      OIDCConfig
+}
+
+func NewOIDCService(auth *AuthService, clients ports.ClientRepository, consents ports.ConsentRepository, codes ports.AuthorizationCodeRepository, cfg OIDCConfig) *OIDCService {
+	cfg.Issuer = strings.TrimSuffix(cfg.Issuer, "/")
+	return &OIDCService{auth: auth, clients: clients, consents: consents, codes: codes, cfg: cfg}
+}
+
+func (s *OIDCService) Discovery() *domain.OIDCDiscovery {
+	return &domain.OIDCDiscovery{
+		Issuer:                            s.cfg.Issuer,
+		AuthorizationEndpoint:             s.cfg.Issuer + "/authorize",
+		TokenEndpoint:                     s.cfg.Issuer + "/token",
+		UserinfoEndpoint:                  s.cfg.Issuer + "/userinfo",
+		JWKSURI:                           s.cfg.Issuer + "/.well-known/jwks.json",
+		ScopesSupported:                   []string{domain.ScopeOpenID, domain.ScopeProfile, domain.ScopeEmail},
+		ResponseTypesSupported:            []string{"code"},
+		GrantTypesSupported:               []string{"authorization_code", "client_credentials"},
+		SubjectTypesSupported:             []string{"public"},
+		IDTokenSigningAlgValuesSupported:  []string{s.auth.keys.Algorithm()},
+		TokenEndpointAuthMethodsSupported: []string{"client_secret_basic", "client_secret_post", "none"},
+		CodeChallengeMethodsSupported:     []string{"S256"},
+		ClaimsSupported:                   []string{"sub", "iss", "aud", "exp", "iat", "auth_time", "nonce", "email", "email_verified", "name", "preferred_username"},
+	}
+}
+
+// ValidateAuthorizationRequest prüft die Parameter von /authorize. Bei
+// ErrUnknownOIDCClient und ErrInvalidRedirectURI wird nicht weitergeleitet,
+// alle anderen Fehler sind OAuthErrors für die redirect_uri.
+func (s *OIDCService) ValidateAuthorizationRequest(req domain.AuthorizationRequest) (*domain.Client, []string, error) {
+	client, err := s.clients.FindByID(req.ClientID)
+	if err != nil || client.Revoked || len(client.RedirectURIs) == 0 {
+		return nil, nil, ErrUnknownOIDCClient
+	}
+	if !client.HasRedirectURI(req.RedirectURI) {
+		return nil, nil, ErrInvalidRedirectURI
+	}
+
+	if req.ResponseType != "code" {
+		return client, nil, &OAuthError{Code: "unsupported_response_type", Description: "only response_type=code is supported"}
+	}
+	scopes := strings.Fields(req.Scope)
+	if !slices.Contains(scopes, domain.ScopeOpenID) {
+		return client, nil, &OAuthError{Code: "invalid_scope", Description: "scope must include openid"}
+	}
+	for _, scope := range scopes {
+		if !domain.IsOIDCScope(scope) || !client.HasScope(scope) {
+			return client, nil, &OAuthError{Code: "invalid_scope", Description: "scope " + scope + " not allowed"}
+		}
+	}
+	if req.CodeChallenge == "" || req.CodeChallengeMethod != "S256" {
+		return client, nil, &OAuthError{Code: "invalid_request", Description: "PKCE with code_challenge_method=S256 is required"}
+	}
+	// Es gibt keine Browser-Sitzung beim auth-service, eine Anmeldung ohne
+	// Interaktion ist daher nie möglich.
+	if req.Prompt == "none" {
+		return client, nil, &OAuthError{Code: "login_required"}
+	}
+	return client, scopes, nil
+}
+
+// Authorize meldet den User mit denselben Regeln wie /login an (Sperre,
+// deaktivierte Konten, Pflicht-MFA). Fehlt die Zustimmung für die Scopes,
+// enthält das Ergebnis ein signiertes Ticket für Consent.
+func (s *OIDCService) Authorize(ctx context.Context, req domain.AuthorizationRequest, email, password, mfaCode, ip string) (*AuthorizeResult, error) {
+	client, scopes, err := s.ValidateAuthorizationRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.auth.Authenticate(ctx, email, password, ip)
+	if err != nil {
+		return nil, err
+	}
+	// Der eingeschränkte Login für unbestätigte Konten gilt hier nicht
+	if !user.Verified {
+		return nil, ErrEmailNotVerified
+	}
+	if user.MFARequired() {
+		if !user.MFAEnabled {
+			return nil, ErrMFAEnrollmentRequired
+		}
+		if mfaCode == "" {
+			return nil, ErrMFACodeRequired
+		}
+		failures, err := s.auth.beginLoginAttempt(ctx, user)
+		if err != nil {
+			s.auth.loginFailed(user, ip, "blocked")
+			return nil, err
+		}
+		ok, err := s.auth.checkMFACode(ctx, user, mfaCode)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			s.auth.loginFailed(user, ip, "invalid_mfa_code")
+			s.auth.lockIfExceeded(ctx, user, failures, ip)
+			return nil, ErrInvalidMFACode
+		}
+		s.auth.resetFailedLogins(ctx, user)
+	}
+
+	now := time.Now()
+	s.auth.emit(&domain.AuthEvent{
+		EventType: domain.EventUserLoggedIn,
+		UserID:    user.ID,
+		Email:     user.Email,
+		IP:        ip,
+		Data: map[string]interface{}{
+			"method":    "oidc",
+			"client_id": client.ID,
+		},
+	})
+
+	consent, err := s.consents.Find(ctx, user.ID, client.ID)
+	if err == nil && consent.Covers(scopes) && req.Prompt != "consent" {
+		redirect, err := s.issueCode(ctx, client, user.ID, req, scopes, now)
+		if err != nil {
+			return nil, err
+		}
+		return &AuthorizeResult{RedirectURL: redirect, Client: client, Scopes: scopes}, nil
+	}
+
+	ticket, err := s.auth.keys.Sign(jwt.MapClaims{
+		"typ":            consentTicketType,
+		"sub":            user.ID,
+		"client_id":      client.ID,
+		"redirect_uri":   req.RedirectURI,
+		"scope":          strings.Join(scopes, " "),
+		"state":          req.State,
+		"nonce":          req.Nonce,
+		"code_challenge": req.CodeChallenge,
+		"auth_time":      now.Unix(),
+		"iat":            now.Unix(),
+		"exp":            now.Add(consentTicketExpiry).Unix(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &AuthorizeResult{ConsentTicket: ticket, Client: client, Scopes: scopes}, nil
+}
+
+// Consent wertet die Entscheidung des Users aus und liefert die Weiterleitung
+// zum Client, mit Code oder mit error=access_denied.
+func (s *OIDCService) Consent(ctx context.Context, ticket string, approve bool) (string, error) {
+	claims := jwt.MapClaims{}
+	token, err := jwt.ParseWithClaims(ticket, claims, s.auth.keys.Keyfunc, jwt.WithExpirationRequired())
+	if err != nil || !token.Valid || claims["typ"] != consentTicketType {
+		return "", ErrInvalidToken
+	}
+	userID, _ := claims["sub"].(string)
+	authTime, _ := claims["auth_time"].(float64)
+	req := domain.AuthorizationRequest{
+		ResponseType:        "code",
+		CodeChallengeMethod: "S256",
+	}
+	req.ClientID, _ = claims["client_id"].(string)
+	req.RedirectURI, _ = claims["redirect_uri"].(string)
+	req.Scope, _ = claims["scope"].(string)
+	req.State, _ = claims["state"].(string)
+	req.Nonce, _ = claims["nonce"].(string)
+	req.CodeChallenge, _ = claims["code_challenge"].(string)
+
+	// Der Client könnte inzwischen gesperrt worden sein
+	client, scopes, err := s.ValidateAuthorizationRequest(req)
+	if err != nil {
+		return "", err
+	}
+	if !approve {
+		return s.ErrorRedirect(req, &OAuthError{Code: "access_denied"}), nil
+	}
+
+	err = s.consents.Save(ctx, &domain.Consent{
+		UserID:    userID,
+		ClientID:  client.ID,
+		Scopes:    scopes,
+		GrantedAt: time.Now(),
+	})
+	if err != nil {
+		return "", err
+	}
+	return s.issueCode(ctx, client, userID, req, scopes, time.Unix(int64(authTime), 0))
+}
+
+func (s *OIDCService) issueCode(ctx context.Context, client *domain.Client, userID string, req domain.AuthorizationRequest, scopes []string, authTime time.Time) (string, error) {
+	code, err := randomToken(32)
+	if err != nil {
+		return "", err
+	}
+	err = s.codes.Create(ctx, &domain.AuthorizationCode{
+		CodeHash:      hashToken(code),
+		ClientID:      client.ID,
+		UserID:        userID,
+		RedirectURI:   req.RedirectURI,
+		Scopes:        scopes,
+		Nonce:         req.Nonce,
+		CodeChallenge: req.CodeChallenge,
+		AuthTime:      authTime,
+		ExpiresAt:     time.Now().Add(s.cfg.CodeExpiry),
+	})
+	if err != nil {
+		return "", err
+	}
+	return s.redirect(req, url.Values{"code": {code}}), nil
+}
+
+// ErrorRedirect baut die Weiterleitung für einen Fehler. Nur nach erfolgreicher
+// Prüfung von Client und redirect_uri verwenden.
+func (s *OIDCService) ErrorRedirect(req domain.AuthorizationRequest, oauthErr *OAuthError) string {
+	params := url.Values{"error": {oauthErr.Code}}
+	if oauthErr.Description != "" {
+		params.Set("error_description", oauthErr.Description)
+	}
+	return s.redirect(req, params)
+}
+
+func (s *OIDCService) redirect(req domain.AuthorizationRequest, params url.Values) string {
+	if req.State != "" {
+		params.Set("state", req.State)
+	}
+	// RFC 9207: iss schützt Clients mit mehreren Providern vor Mix-up-Angriffen
+	params.Set("iss", s.cfg.Issuer)
+	separator := "?"
+	if strings.Contains(req.RedirectURI, "?") {
+		separator = "&"
+	}
+	return req.RedirectURI + separator + params.Encode()
+}
+
+// ExchangeCode löst einen Authorization Code ein (grant_type=authorization_code).
+// Vertrauliche Clients authentifizieren sich mit Secret, öffentliche nur über
+// den code_verifier.
+func (s *OIDCService) ExchangeCode(ctx context.Context, clientID, clientSecret, code, redirectURI, codeVerifier string) (*domain.OIDCTokenResponse, error) {
+	client, err := s.clients.FindByID(clientID)
+	if err != nil || client.Revoked || len(client.RedirectURIs) == 0 {
+		return nil, &OAuthError{Code: "invalid_client"}
+	}
+	if client.Public {
+		if clientSecret != "" {
+			return nil, &OAuthError{Code: "invalid_client"}
+		}
+	} else if clientSecret == "" || subtle.ConstantTimeCompare([]byte(client.SecretHash), []byte(hashToken(clientSecret))) != 1 {
+		return nil, &OAuthError{Code: "invalid_client"}
+	}
+
+	stored, err := s.codes.Consume(ctx, hashToken(code))
+	if err != nil || stored.ClientID != client.ID || stored.RedirectURI != redirectURI || time.Now().After(stored.ExpiresAt) {
+		return nil, &OAuthError{Code: "invalid_grant"}
+	}
+	if !verifyPKCE(codeVerifier, stored.CodeChallenge) {
+		return nil, &OAuthError{Code: "invalid_grant", Description: "code_verifier does not match"}
+	}
+
+	user, err := s.auth.repo.FindByID(ctx, stored.UserID)
+	if err != nil || user.Disabled || user.PasswordResetRequired {
+		return nil, &OAuthError{Code: "invalid_grant"}
+	}
+
+	jti, err := newID()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	expiresAt := now.Add(s.auth.cfg.AccessExpiry)
+	scope := strings.Join(stored.Scopes, " ")
+	accessToken, err := s.auth.keys.Sign(jwt.MapClaims{
+		"jti":       jti,
+		"iss":       s.cfg.Issuer,
+		"sub":       user.ID,
+		"user_id":   user.ID,
+		"client_id": client.ID,
+		"scope":     scope,
+		"typ":       domain.TokenTypeOIDC,
+		"iat":       issuedAtValue(now),
+		"exp":       expiresAt.Unix(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	idClaims := jwt.MapClaims{
+		"iss":       s.cfg.Issuer,
+		"sub":       user.ID,
+		"aud":       client.ID,
+		"typ":       domain.TokenTypeID,
+		"auth_time": stored.AuthTime.Unix(),
+		"iat":       now.Unix(),
+		"exp":       expiresAt.Unix(),
+	}
+	if stored.Nonce != "" {
+		idClaims["nonce"] = stored.Nonce
+	}
+	for k, v := range userClaims(user, stored.Scopes) {
+		idClaims[k] = v
+	}
+	idToken, err := s.auth.keys.Sign(idClaims)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.OIDCTokenResponse{
+		AccessToken: accessToken,
+		TokenType:   "Bearer",
+		ExpiresIn:   int(s.auth.cfg.AccessExpiry.Seconds()),
+		Scope:       scope,
+		IDToken:     idToken,
+	}, nil
+}
+
+// UserInfo liefert die Claims zum Access-Token aus dem Code-Flow, abhängig von
+// den freigegebenen Scopes.
+func (s *OIDCService) UserInfo(ctx context.Context, accessToken string) (map[string]interface{}, error) {
+	claims, err := s.auth.ValidateAccessToken(accessToken)
+	if err != nil || claims.Type != domain.TokenTypeOIDC {
+		return nil, ErrInvalidToken
+	}
+	user, err := s.auth.repo.FindByID(ctx, claims.UserID)
+	if err != nil || user.Disabled {
+		return nil, ErrInvalidToken
+	}
+	info := userClaims(user, strings.Fields(claims.Scope))
+	info["sub"] = user.ID
+	return info, nil
+}
+
+func userClaims(user *domain.User, scopes []string) map[string]interface{} {
+	claims := map[string]interface{}{}
+	if slices.Contains(scopes, domain.ScopeEmail) {
+		claims["email"] = user.Email
+		claims["email_verified"] = user.Verified
+	}
+	if slices.Contains(scopes, domain.ScopeProfile) {
+		claims["preferred_username"] = user.Email
+		if user.DisplayName != "" {
+			claims["name"] = user.DisplayName
+		}
+	}
+	return claims
+}
+
+// verifyPKCE prüft code_verifier gegen die S256-Challenge (RFC 7636).
+func verifyPKCE(verifier, challenge string) bool {
+	if len(verifier) < 43 || len(verifier) > 128 {
+		return false
+	}
+	sum := sha256.Sum256([]byte(verifier))
+	expected := base64.RawURLEncoding.EncodeToString(sum[:])
+	return subtle.ConstantTimeCompare([]byte(expected), []byte(challenge)) == 1
+}
@@ -0,0 +1,55 @@
+package service
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"strings"
+	"testing"
+)
+
+// Beispiel aus RFC 7636, Anhang B
+const (
+	rfc7636Verifier  = "dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"
+	rfc7636Challenge = "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM"
+)
+
+func TestVerifyPKCE(t *testing.T) {
+	tests := []struct {
+		name      string
+		verifier  string
+		challenge string
+		want      bool
+	}{
+		{"RFC 7636 example", rfc7636Verifier, rfc7636Challenge, true},
+		{"wrong verifier", strings.Replace(rfc7636Verifier, "d", "e", 1), rfc7636Challenge, false},
+		{"plain method", rfc7636Verifier, rfc7636Verifier, false},
+		{"padded challenge", rfc7636Verifier, rfc7636Challenge + "=", false},
+		{"empty challenge", rfc7636Verifier, "", false},
+		{"empty verifier", "", rfc7636Challenge, false},
+		{"verifier too short", rfc7636Verifier[:42], rfc7636Challenge, false},
+		{"verifier too long", strings.Repeat("a", 129), rfc7636Challenge, false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := verifyPKCE(tc.verifier, tc.challenge); got != tc.want {
+				t.Errorf("verifyPKCE(%q, %q) = %v, want %v", tc.verifier, tc.challenge, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestVerifyPKCELengthLimits(t *testing.T) {
+	// 43 und 128 Zeichen sind die Grenzen aus RFC 7636, Abschnitt 4.1
+	for _, n := range []int{43, 128} {
+		verifier := strings.Repeat("a", n)
+		if !verifyPKCE(verifier, pkceChallenge(verifier)) {
+			t.Errorf("verifier with %d characters rejected", n)
+		}
+	}
+}
+
+// pkceChallenge berechnet die Challenge so, wie ein Client es tut.
+func pkceChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
@@ -0,0 +1,165 @@
+package service
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Werte für HasherConfig.Algorithm
+const (
+	HashArgon2id = "argon2id"
+	HashBcrypt   = "bcrypt"
+)
+
+// bcrypt ignoriert alles nach 72 Bytes, solche Passwörter lehnen wir ab.
+const bcryptMaxPasswordBytes = 72
+
+// PasswordHasher erzeugt und prüft Passwort-Hashes im PHC-Format.
+type PasswordHasher interface {
+	Hash(password string) (string, error)
+	Verify(hash, password string) bool
+	// NeedsRehash meldet Hashes mit einem anderen Verfahren oder schwächeren
+	// Parametern als konfiguriert.
+	NeedsRehash(hash string) bool
+}
+
+// Argon2Params sind die Kostenparameter für argon2id. Memory in KiB.
+type Argon2Params struct {
+	Memory      uint32
+	Iterations  uint32
+	Parallelism uint8
+	SaltLength  uint32
+	KeyLength   uint32
+}
+
+// HasherConfig legt fest, womit neue Hashes erzeugt werden. Hashes beider
+// Verfahren werden immer akzeptiert.
+type HasherConfig struct {
+	Algorithm  string
+	Argon2     Argon2Params
+	BcryptCost int
+}
+
+type passwordHasher struct {
+	cfg HasherConfig
+}
+
+func NewPasswordHasher(cfg HasherConfig) (PasswordHasher, error) {
+	switch cfg.Algorithm {
+	case HashArgon2id:
+		if cfg.Argon2.Memory == 0 || cfg.Argon2.Iterations == 0 || cfg.Argon2.Parallelism == 0 {
+			return nil, errors.New("argon2id memory, iterations and parallelism must be positive")
+		}
+		if cfg.Argon2.SaltLength == 0 {
+			cfg.Argon2.SaltLength = 16
+		}
+		if cfg.Argon2.KeyLength == 0 {
+			cfg.Argon2.KeyLength = 32
+		}
+	case HashBcrypt:
+		if cfg.BcryptCost < bcrypt.MinCost || cfg.BcryptCost > bcrypt.MaxCost {
+			return nil, fmt.Errorf("bcrypt cost must be between %d and %d", bcrypt.MinCost, bcrypt.MaxCost)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported password hash algorithm %q", cfg.Algorithm)
+	}
+	return &passwordHasher{cfg: cfg}, nil
+}
+
+func (h *passwordHasher) Hash(password string) (string, error) {
+	if h.cfg.Algorithm == HashBcrypt {
+		if len(password) > bcryptMaxPasswordBytes {
+			return "", &PasswordPolicyError{Reason: fmt.Sprintf("must be at most %d bytes", bcryptMaxPasswordBytes)}
+		}
+		hash, err := bcrypt.GenerateFromPassword([]byte(password), h.cfg.BcryptCost)
+		if err != nil {
+			return "", err
+		}
+		return string(hash), nil
+	}
+
+	p := h.cfg.Argon2
+	salt := make([]byte, p.SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	key := argon2.IDKey([]byte(password), salt, p.Iterations, p.Memory, p.Parallelism, p.KeyLength)
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s", argon2.Version, p.Memory, p.Iterations, p.Parallelism,
+		base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(key)), nil
+}
+
+func (h *passwordHasher) Verify(hash, password string) bool {
+	switch {
+	case strings.HasPrefix(hash, "$argon2id$"):
+		p, salt, key, err := decodeArgon2id(hash)
+		if err != nil {
+			return false
+		}
+		actual := argon2.IDKey([]byte(password), salt, p.Iterations, p.Memory, p.Parallelism, uint32(len(key)))
+		return subtle.ConstantTimeCompare(actual, key) == 1
+	case isBcryptHash(hash):
+		return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil
+	}
+	return false
+}
+
+func (h *passwordHasher) NeedsRehash(hash string) bool {
+	switch {
+	case strings.HasPrefix(hash, "$argon2id$"):
+		if h.cfg.Algorithm != HashArgon2id {
+			return true
+		}
+		p, salt, key, err := decodeArgon2id(hash)
+		if err != nil {
+			return true
+		}
+		want := h.cfg.Argon2
+		return p.Memory < want.Memory || p.Iterations < want.Iterations || p.Parallelism < want.Parallelism ||
+			uint32(len(salt)) < want.SaltLength || uint32(len(key)) < want.KeyLength
+	case isBcryptHash(hash):
+		if h.cfg.Algorithm != HashBcrypt {
+			return true
+		}
+		cost, err := bcrypt.Cost([]byte(hash))
+		return err != nil || cost < h.cfg.BcryptCost
+	}
+	return true
+}
+
+// decodeArgon2id zerlegt $argon2id$v=19$m=...,t=...,p=...$salt$key.
+func decodeArgon2id(hash string) (Argon2Params, []byte, []byte, error) {
+	var p Argon2Params
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 {
+		return p, nil, nil, errors.New("malformed argon2id hash")
+	}
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return p, nil, nil, errors.New("unsupported argon2 version")
+	}
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &p.Memory, &p.Iterations, &p.Parallelism); err != nil {
+		return p, nil, nil, errors.New("malformed argon2id parameters")
+	}
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return p, nil, nil, err
+	}
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil || len(key) == 0 {
+		return p, nil, nil, errors.New("malformed argon2id key")
+	}
+	p.SaltLength = uint32(len(salt))
+	p.KeyLength = uint32(len(key))
+	return p, salt, key, nil
+}
+
+func isBcryptHash(hash string) bool {
+	return strings.HasPrefix(hash, "$2a$") || strings.HasPrefix(hash, "$2b$") || strings.HasPrefix(hash, "$2y$")
+}
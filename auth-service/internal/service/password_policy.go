@@ -0,0 +1,103 @@
+package service
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+	"unicode/utf8"
+)
+
+const maxPasswordLength = 128
+
+// PasswordPolicyError beschreibt, warum ein neues Passwort abgelehnt wurde.
+type PasswordPolicyError struct {
+	Reason string
+}
+
+func (e *PasswordPolicyError) Error() string {
+	return "password " + e.Reason
+}
+
+// PasswordPolicy wird bei Registrierung und jeder Passwortänderung geprüft.
+type PasswordPolicy struct {
+	MinLength int
+	// breached enthält SHA-1-Hashes (hex, groß) bekannter geleakter Passwörter.
+	breached map[string]struct{}
+}
+
+// LoadPasswordPolicy liest optional eine lokale Liste geleakter Passwörter.
+// Pro Zeile steht entweder ein Passwort im Klartext oder ein SHA-1-Hash im
+// Format der Have-I-Been-Pwned-Downloads (HASH oder HASH:ANZAHL). Leere
+// Zeilen und Zeilen mit # werden übersprungen.
+func LoadPasswordPolicy(minLength int, breachedListPath string) (*PasswordPolicy, error) {
+	policy := &PasswordPolicy{MinLength: minLength, breached: map[string]struct{}{}}
+	if breachedListPath == "" {
+		return policy, nil
+	}
+
+	f, err := os.Open(breachedListPath)
+	if err != nil {
+		return nil, fmt.Errorf("open breached password list: %w", err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if hash, _, _ := strings.Cut(line, ":"); isSHA1Hex(hash) {
+			policy.breached[strings.ToUpper(hash)] = struct{}{}
+			continue
+		}
+		// Klartexteinträge gelten unabhängig von Groß-/Kleinschreibung.
+		policy.breached[sha1Hex(strings.ToLower(line))] = struct{}{}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read breached password list: %w", err)
+	}
+	return policy, nil
+}
+
+// Check gibt einen PasswordPolicyError zurück, wenn das Passwort nicht zulässig ist.
+func (p *PasswordPolicy) Check(password string) error {
+	length := utf8.RuneCountInString(password)
+	if length < p.MinLength {
+		return &PasswordPolicyError{Reason: fmt.Sprintf("must be at least %d characters", p.MinLength)}
+	}
+	if length > maxPasswordLength {
+		return &PasswordPolicyError{Reason: fmt.Sprintf("must be at most %d characters", maxPasswordLength)}
+	}
+	if p.isBreached(password) {
+		return &PasswordPolicyError{Reason: "appears in a list of breached passwords"}
+	}
+	return nil
+}
+
+func (p *PasswordPolicy) isBreached(password string) bool {
+	if len(p.breached) == 0 {
+		return false
+	}
+	if _, ok := p.breached[sha1Hex(password)]; ok {
+		return true
+	}
+	_, ok := p.breached[sha1Hex(strings.ToLower(password))]
+	return ok
+}
+
+func sha1Hex(s string) string {
+	sum := sha1.Sum([]byte(s))
+	return strings.ToUpper(hex.EncodeToString(sum[:]))
+}
+
+func isSHA1Hex(s string) bool {
+	if len(s) != sha1.Size*2 {
+		return false
+	}
+	_, err := hex.DecodeString(s)
+	return err == nil
+}
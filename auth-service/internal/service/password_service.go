@@ -0,0 +1,112 @@
+package service
+
+import (
+	"auth-service/internal/domain"
+	"auth-service/internal/ports"
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+var (
+	ErrInvalidResetToken = errors.New("invalid or expired reset token")
+	ErrPasswordRequired  = errors.New("password required")
+)
+
+type PasswordService struct {
+	users       ports.UserRepository
+	tokens      ports.OneTimeTokenRepository
+	auth        *AuthService
+	mailer      ports.Mailer
+	hasher      PasswordHasher
+	policy      *PasswordPolicy
+	resetExpiry time.Duration
+	baseURL     string
+}
+
+func NewPasswordService(users ports.UserRepository, tokens ports.OneTimeTokenRepository, auth *AuthService, mailer ports.Mailer, hasher PasswordHasher, policy *PasswordPolicy, resetExpirySeconds int, baseURL string) *PasswordService {
+	return &PasswordService{
+		users:       users,
+		tokens:      tokens,
+		auth:        auth,
+		mailer:      mailer,
+		hasher:      hasher,
+		policy:      policy,
+		resetExpiry: time.Duration(resetExpirySeconds) * time.Second,
+		baseURL:     baseURL,
+	}
+}
+
+// ForgotPassword verschickt einen Reset-Link. Für unbekannte Adressen passiert
+// nichts, der Aufrufer erfährt davon aber nichts (keine User-Enumeration).
+func (s *PasswordService) ForgotPassword(ctx context.Context, email string) error {
+	user, err := s.users.FindByEmail(ctx, email)
+	if err != nil {
+		return nil
+	}
+	return s.SendResetLink(user)
+}
+
+// SendResetLink erzeugt ein neues Reset-Token und verschickt den Link.
+func (s *PasswordService) SendResetLink(user *domain.User) error {
+	// Ältere, noch offene Links werden mit jedem neuen Antrag ungültig.
+	if err := s.tokens.InvalidateForUser(user.ID, domain.TokenPurposePasswordReset); err != nil {
+		return err
+	}
+
+	token, err := randomToken(32)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	err = s.tokens.Create(&domain.OneTimeToken{
+		UserID:    user.ID,
+		Purpose:   domain.TokenPurposePasswordReset,
+		TokenHash: hashToken(token),
+		ExpiresAt: now.Add(s.resetExpiry),
+		CreatedAt: now,
+	})
+	if err != nil {
+		return err
+	}
+
+	link := fmt.Sprintf("%s/reset-password?token=%s", s.baseURL, url.QueryEscape(token))
+	return s.mailer.Send(&domain.Mail{
+		To:      user.Email,
+		Subject: "Passwort zurücksetzen",
+		Body: fmt.Sprintf("Hallo,\n\nüber den folgenden Link kannst du ein neues Passwort setzen:\n\n%s\n\n"+
+			"Der Link ist %d Minuten gültig und kann nur einmal verwendet werden. "+
+			"Falls du das nicht angefordert hast, kannst du diese Mail ignorieren.\n",
+			link, int(s.resetExpiry.Minutes())),
+	})
+}
+
+// ResetPassword löst das Token ein, setzt das neue Passwort und meldet alle
+// bestehenden Sitzungen ab. Auch noch gültige Access-Tokens werden gesperrt.
+func (s *PasswordService) ResetPassword(ctx context.Context, token, newPassword, ip string) error {
+	if newPassword == "" {
+		return ErrPasswordRequired
+	}
+	// Vor dem Einlösen prüfen, damit der Link bei einem abgelehnten Passwort
+	// gültig bleibt.
+	if err := s.policy.Check(newPassword); err != nil {
+		return err
+	}
+
+	stored, err := s.tokens.Consume(hashToken(token), domain.TokenPurposePasswordReset)
+	if err != nil {
+		return ErrInvalidResetToken
+	}
+
+	hash, err := s.hasher.Hash(newPassword)
+	if err != nil {
+		return err
+	}
+	if err := s.users.UpdatePassword(ctx, stored.UserID, hash); err != nil {
+		return err
+	}
+
+	return s.auth.RevokeAllTokens(ctx, stored.UserID, stored.UserID, ip)
+}
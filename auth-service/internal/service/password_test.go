@@ -0,0 +1,162 @@
+package service
+
+import (
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Kleine Kostenparameter, damit die Tests schnell bleiben
+var testArgon2 = Argon2Params{Memory: 64, Iterations: 1, Parallelism: 1}
+
+func newTestHasher(t *testing.T, cfg HasherConfig) PasswordHasher {
+	t.Helper()
+	h, err := NewPasswordHasher(cfg)
+	if err != nil {
+		t.Fatalf("NewPasswordHasher(%+v): %v", cfg, err)
+	}
+	return h
+}
+
+func TestPasswordHasherRoundTrip(t *testing.T) {
+	tests := []struct {
+		name   string
+		cfg    HasherConfig
+		prefix string
+	}{
+		{"argon2id", HasherConfig{Algorithm: HashArgon2id, Argon2: testArgon2}, "$argon2id$v=19$m=64,t=1,p=1$"},
+		{"bcrypt", HasherConfig{Algorithm: HashBcrypt, BcryptCost: bcrypt.MinCost}, "$2a$04$"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			h := newTestHasher(t, tc.cfg)
+			hash, err := h.Hash("correct horse battery staple")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.HasPrefix(hash, tc.prefix) {
+				t.Errorf("hash %q does not start with %q", hash, tc.prefix)
+			}
+			if !h.Verify(hash, "correct horse battery staple") {
+				t.Error("correct password rejected")
+			}
+			if h.Verify(hash, "correct horse battery stapler") {
+				t.Error("wrong password accepted")
+			}
+			if h.NeedsRehash(hash) {
+				t.Error("fresh hash reported as needing rehash")
+			}
+
+			again, err := h.Hash("correct horse battery staple")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if again == hash {
+				t.Error("hashes of the same password are identical, salt missing")
+			}
+		})
+	}
+}
+
+func TestPasswordHasherVerifiesOtherAlgorithm(t *testing.T) {
+	argon := newTestHasher(t, HasherConfig{Algorithm: HashArgon2id, Argon2: testArgon2})
+	bc := newTestHasher(t, HasherConfig{Algorithm: HashBcrypt, BcryptCost: bcrypt.MinCost})
+
+	argonHash, err := argon.Hash("secret-password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	bcryptHash, err := bc.Hash("secret-password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bc.Verify(argonHash, "secret-password") {
+		t.Error("bcrypt hasher rejected argon2id hash")
+	}
+	if !argon.Verify(bcryptHash, "secret-password") {
+		t.Error("argon2id hasher rejected bcrypt hash")
+	}
+}
+
+func TestPasswordHasherNeedsRehash(t *testing.T) {
+	weakArgon := newTestHasher(t, HasherConfig{Algorithm: HashArgon2id, Argon2: testArgon2})
+	weakArgonHash, err := weakArgon.Hash("secret-password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	weakBcrypt := newTestHasher(t, HasherConfig{Algorithm: HashBcrypt, BcryptCost: bcrypt.MinCost})
+	weakBcryptHash, err := weakBcrypt.Hash("secret-password")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		cfg  HasherConfig
+		hash string
+		want bool
+	}{
+		{"same argon2id parameters", HasherConfig{Algorithm: HashArgon2id, Argon2: testArgon2}, weakArgonHash, false},
+		{"more argon2id memory", HasherConfig{Algorithm: HashArgon2id, Argon2: Argon2Params{Memory: 128, Iterations: 1, Parallelism: 1}}, weakArgonHash, true},
+		{"more argon2id iterations", HasherConfig{Algorithm: HashArgon2id, Argon2: Argon2Params{Memory: 64, Iterations: 2, Parallelism: 1}}, weakArgonHash, true},
+		{"more argon2id parallelism", HasherConfig{Algorithm: HashArgon2id, Argon2: Argon2Params{Memory: 64, Iterations: 1, Parallelism: 2}}, weakArgonHash, true},
+		{"longer argon2id key", HasherConfig{Algorithm: HashArgon2id, Argon2: Argon2Params{Memory: 64, Iterations: 1, Parallelism: 1, KeyLength: 64}}, weakArgonHash, true},
+		{"weaker argon2id configured", HasherConfig{Algorithm: HashArgon2id, Argon2: Argon2Params{Memory: 32, Iterations: 1, Parallelism: 1}}, weakArgonHash, false},
+		{"argon2id hash with bcrypt configured", HasherConfig{Algorithm: HashBcrypt, BcryptCost: bcrypt.MinCost}, weakArgonHash, true},
+		{"same bcrypt cost", HasherConfig{Algorithm: HashBcrypt, BcryptCost: bcrypt.MinCost}, weakBcryptHash, false},
+		{"higher bcrypt cost", HasherConfig{Algorithm: HashBcrypt, BcryptCost: bcrypt.MinCost + 1}, weakBcryptHash, true},
+		{"bcrypt hash with argon2id configured", HasherConfig{Algorithm: HashArgon2id, Argon2: testArgon2}, weakBcryptHash, true},
+		{"unknown format", HasherConfig{Algorithm: HashArgon2id, Argon2: testArgon2}, "plaintext", true},
+		{"malformed argon2id", HasherConfig{Algorithm: HashArgon2id, Argon2: testArgon2}, "$argon2id$v=19$m=64$abc", true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			h := newTestHasher(t, tc.cfg)
+			if got := h.NeedsRehash(tc.hash); got != tc.want {
+				t.Errorf("NeedsRehash = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestPasswordHasherRejectsMalformedHashes(t *testing.T) {
+	h := newTestHasher(t, HasherConfig{Algorithm: HashArgon2id, Argon2: testArgon2})
+	for _, hash := range []string{
+		"",
+		"secret-password",
+		"$argon2i$v=19$m=64,t=1,p=1$c29tZXNhbHQ$AAAA",
+		"$argon2id$v=16$m=64,t=1,p=1$c29tZXNhbHQ$AAAA",
+		"$argon2id$v=19$m=64,t=1,p=1$c29tZXNhbHQ$",
+		"$argon2id$v=19$m=64,t=1,p=1$!!!$AAAA",
+		"$2a$04$invalid",
+	} {
+		if h.Verify(hash, "secret-password") {
+			t.Errorf("Verify accepted malformed hash %q", hash)
+		}
+	}
+}
+
+func TestBcryptRejectsLongPasswords(t *testing.T) {
+	h := newTestHasher(t, HasherConfig{Algorithm: HashBcrypt, BcryptCost: bcrypt.MinCost})
+	if _, err := h.Hash(strings.Repeat("a", bcryptMaxPasswordBytes)); err != nil {
+		t.Errorf("%d byte password rejected: %v", bcryptMaxPasswordBytes, err)
+	}
+	_, err := h.Hash(strings.Repeat("a", bcryptMaxPasswordBytes+1))
+	if _, ok := err.(*PasswordPolicyError); !ok {
+		t.Errorf("Hash of %d byte password: err = %v, want *PasswordPolicyError", bcryptMaxPasswordBytes+1, err)
+	}
+}
+
+func TestNewPasswordHasherValidatesConfig(t *testing.T) {
+	for _, cfg := range []HasherConfig{
+		{Algorithm: "md5"},
+		{Algorithm: HashArgon2id},
+		{Algorithm: HashBcrypt, BcryptCost: bcrypt.MinCost - 1},
+		{Algorithm: HashBcrypt, BcryptCost: bcrypt.MaxCost + 1},
+	} {
+		if _, err := NewPasswordHasher(cfg); err == nil {
+			t.Errorf("NewPasswordHasher(%+v) accepted invalid config", cfg)
+		}
+	}
+}
@@ -0,0 +1,246 @@
+package service
+
+import (
+	"auth-service/internal/domain"
+	"auth-service/internal/ports"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+var (
+	ErrWrongPassword           = errors.New("current password is incorrect")
+	ErrInvalidDisplayName      = errors.New("display name must be at most 100 characters")
+	ErrInvalidCurrency         = errors.New("preferred currency must be an ISO 4217 code")
+	ErrInvalidAddress          = errors.New("address requires name, street, postal_code, city and a 2-letter country code")
+	ErrTooManyAddresses        = errors.New("at most 10 addresses allowed")
+	ErrMultipleDefaultAddress  = errors.New("only one address can be the default")
+	ErrInvalidEmail            = errors.New("invalid email address")
+	ErrEmailUnchanged          = errors.New("new email equals current email")
+	ErrEmailTaken              = errors.New("email already in use")
+	ErrInvalidEmailChangeToken = errors.New("invalid or expired email change token")
+)
+
+const maxAddresses = 10
+
+var (
+	currencyPattern = regexp.MustCompile(`^[A-Z]{3}$`)
+	countryPattern  = regexp.MustCompile(`^[A-Z]{2}$`)
+)
+
+// ProfileService verwaltet die Daten, die ein User selbst über /me ändert.
+type ProfileService struct {
+	users             ports.UserRepository
+	tokens            ports.OneTimeTokenRepository
+	mailer            ports.Mailer
+	auth              *AuthService
+	emailChangeExpiry time.Duration
+	baseURL           string
+}
+
+func NewProfileService(users ports.UserRepository, tokens ports.OneTimeTokenRepository, mailer ports.Mailer, auth *AuthService, emailChangeExpirySeconds int, baseURL string) *ProfileService {
+	return &ProfileService{
+		users:             users,
+		tokens:            tokens,
+		mailer:            mailer,
+		auth:              auth,
+		emailChangeExpiry: time.Duration(emailChangeExpirySeconds) * time.Second,
+		baseURL:           baseURL,
+	}
+}
+
+func (s *ProfileService) GetProfile(ctx context.Context, userID string) (*domain.User, error) {
+	user, err := s.users.FindByID(ctx, userID)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+	return user, nil
+}
+
+// UpdateProfile übernimmt nur die gesetzten Felder. Adressen werden als ganze
+// Liste ersetzt; ohne markierte Default-Adresse wird die erste zum Default.
+func (s *ProfileService) UpdateProfile(ctx context.Context, userID string, update domain.ProfileUpdate) (*domain.User, error) {
+	if update.DisplayName != nil {
+		name := strings.TrimSpace(*update.DisplayName)
+		if utf8.RuneCountInString(name) > 100 {
+			return nil, ErrInvalidDisplayName
+		}
+		update.DisplayName = &name
+	}
+	if update.PreferredCurrency != nil {
+		currency := strings.ToUpper(strings.TrimSpace(*update.PreferredCurrency))
+		if currency != "" && !currencyPattern.MatchString(currency) {
+			return nil, ErrInvalidCurrency
+		}
+		update.PreferredCurrency = &currency
+	}
+	if update.Addresses != nil {
+		addresses, err := normalizeAddresses(*update.Addresses)
+		if err != nil {
+			return nil, err
+		}
+		update.Addresses = &addresses
+	}
+
+	if err := s.users.UpdateProfile(ctx, userID, update); err != nil {
+		return nil, ErrUserNotFound
+	}
+	return s.GetProfile(ctx, userID)
+}
+
+func normalizeAddresses(addresses []domain.Address) ([]domain.Address, error) {
+	if len(addresses) > maxAddresses {
+		return nil, ErrTooManyAddresses
+	}
+	defaults := 0
+	for i := range addresses {
+		a := &addresses[i]
+		a.Label = strings.TrimSpace(a.Label)
+		a.Name = strings.TrimSpace(a.Name)
+		a.Street = strings.TrimSpace(a.Street)
+		a.PostalCode = strings.TrimSpace(a.PostalCode)
+		a.City = strings.TrimSpace(a.City)
+		a.Country = strings.ToUpper(strings.TrimSpace(a.Country))
+		if a.Name == "" || a.Street == "" || a.PostalCode == "" || a.City == "" || !countryPattern.MatchString(a.Country) {
+			return nil, ErrInvalidAddress
+		}
+		if a.ID == "" {
+			id, err := newID()
+			if err != nil {
+				return nil, err
+			}
+			a.ID = id
+		}
+		if a.Default {
+			defaults++
+		}
+	}
+	if defaults > 1 {
+		return nil, ErrMultipleDefaultAddress
+	}
+	if defaults == 0 && len(addresses) > 0 {
+		addresses[0].Default = true
+	}
+	return addresses, nil
+}
+
+// ChangePassword setzt ein neues Passwort, wenn das aktuelle stimmt. Alle
+// anderen Sitzungen werden beendet; der Aufrufer erhält neue Tokens.
+func (s *ProfileService) ChangePassword(ctx context.Context, userID, currentPassword, newPassword, ip, userAgent string) (*domain.TokenPair, error) {
+	if newPassword == "" {
+		return nil, ErrPasswordRequired
+	}
+	if err := s.auth.policy.Check(newPassword); err != nil {
+		return nil, err
+	}
+	user, err := s.users.FindByID(ctx, userID)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+	if !s.auth.hasher.Verify(user.Password, currentPassword) {
+		return nil, ErrWrongPassword
+	}
+
+	hash, err := s.auth.hasher.Hash(newPassword)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.users.UpdatePassword(ctx, user.ID, hash); err != nil {
+		return nil, err
+	}
+	if err := s.auth.RevokeAllTokens(ctx, user.ID, user.ID, ip); err != nil {
+		return nil, err
+	}
+	return s.auth.completeLogin(ctx, user, ip, userAgent)
+}
+
+// RequestEmailChange schickt einen Bestätigungslink an die neue Adresse. Erst
+// mit ConfirmEmailChange wird sie übernommen.
+func (s *ProfileService) RequestEmailChange(ctx context.Context, userID, newEmail, password string) error {
+	newEmail = strings.TrimSpace(newEmail)
+	if !strings.Contains(newEmail, "@") {
+		return ErrInvalidEmail
+	}
+	user, err := s.users.FindByID(ctx, userID)
+	if err != nil {
+		return ErrUserNotFound
+	}
+	if !s.auth.hasher.Verify(user.Password, password) {
+		return ErrWrongPassword
+	}
+	newEmail = domain.NormalizeEmail(newEmail)
+	if newEmail == domain.NormalizeEmail(user.Email) {
+		return ErrEmailUnchanged
+	}
+	if _, err := s.users.FindByEmail(ctx, newEmail); err == nil {
+		return ErrEmailTaken
+	}
+
+	if err := s.tokens.InvalidateForUser(user.ID, domain.TokenPurposeEmailChange); err != nil {
+		return err
+	}
+	token, err := randomToken(32)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	err = s.tokens.Create(&domain.OneTimeToken{
+		UserID:    user.ID,
+		Purpose:   domain.TokenPurposeEmailChange,
+		Email:     newEmail,
+		TokenHash: hashToken(token),
+		ExpiresAt: now.Add(s.emailChangeExpiry),
+		CreatedAt: now,
+	})
+	if err != nil {
+		return err
+	}
+
+	link := fmt.Sprintf("%s/confirm-email?token=%s", s.baseURL, url.QueryEscape(token))
+	return s.mailer.Send(&domain.Mail{
+		To:      newEmail,
+		Subject: "Bitte bestätige deine neue E-Mail-Adresse",
+		Body: fmt.Sprintf("Hallo,\n\nbitte bestätige deine neue E-Mail-Adresse über diesen Link:\n\n%s\n\n"+
+			"Der Link ist %d Stunden gültig. Bis dahin bleibt deine bisherige Adresse aktiv.\n",
+			link, int(s.emailChangeExpiry.Hours())),
+	})
+}
+
+// ConfirmEmailChange übernimmt die neue Adresse und informiert die alte.
+func (s *ProfileService) ConfirmEmailChange(ctx context.Context, token string) error {
+	stored, err := s.tokens.Consume(hashToken(token), domain.TokenPurposeEmailChange)
+	if err != nil || stored.Email == "" {
+		return ErrInvalidEmailChangeToken
+	}
+	user, err := s.users.FindByID(ctx, stored.UserID)
+	if err != nil {
+		return ErrInvalidEmailChangeToken
+	}
+	// Die Adresse könnte seit der Anfrage registriert worden sein
+	if _, err := s.users.FindByEmail(ctx, stored.Email); err == nil {
+		return ErrEmailTaken
+	}
+	if err := s.users.UpdateEmail(ctx, user.ID, stored.Email); err != nil {
+		if errors.Is(err, ErrUserExists) {
+			return ErrEmailTaken
+		}
+		return err
+	}
+
+	err = s.mailer.Send(&domain.Mail{
+		To:      user.Email,
+		Subject: "Deine E-Mail-Adresse wurde geändert",
+		Body: fmt.Sprintf("Hallo,\n\ndie E-Mail-Adresse deines Kontos wurde auf %s geändert. "+
+			"Falls du das nicht warst, wende dich bitte an den Support.\n", stored.Email),
+	})
+	if err != nil {
+		log.Printf("Failed to notify %s about email change: %v", user.Email, err)
+	}
+	return nil
+}
@@ -0,0 +1,251 @@
+package service
+
+import (
+	"auth-service/internal/domain"
+	"auth-service/internal/ports"
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// Fakes im Speicher; nicht benötigte Methoden fallen auf das eingebettete
+// nil-Interface und würden panicken.
+type fakeUsers struct {
+	ports.UserRepository
+	users map[string]*domain.User
+}
+
+func (f *fakeUsers) FindByID(ctx context.Context, id string) (*domain.User, error) {
+	user, ok := f.users[id]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	copied := *user
+	return &copied, nil
+}
+
+type fakeRefreshTokens struct {
+	tokens map[string]*domain.RefreshToken
+	// loseRace lässt MarkUsed scheitern, als hätte ein paralleler Request
+	// das Token zwischen FindByHash und MarkUsed verbraucht.
+	loseRace bool
+}
+
+func (f *fakeRefreshTokens) Create(token *domain.RefreshToken) error {
+	copied := *token
+	f.tokens[token.ID] = &copied
+	return nil
+}
+
+func (f *fakeRefreshTokens) FindByHash(hash string) (*domain.RefreshToken, error) {
+	for _, t := range f.tokens {
+		if t.TokenHash == hash {
+			copied := *t
+			return &copied, nil
+		}
+	}
+	return nil, errors.New("not found")
+}
+
+func (f *fakeRefreshTokens) MarkUsed(id, replacedBy string) (bool, error) {
+	t := f.tokens[id]
+	if f.loseRace || t.UsedAt != nil || t.Revoked {
+		return false, nil
+	}
+	now := time.Now()
+	t.UsedAt = &now
+	t.ReplacedBy = replacedBy
+	return true, nil
+}
+
+func (f *fakeRefreshTokens) RevokeFamily(familyID string) error {
+	for _, t := range f.tokens {
+		if t.FamilyID == familyID {
+			t.Revoked = true
+		}
+	}
+	return nil
+}
+
+func (f *fakeRefreshTokens) RevokeAllForUser(userID string) error {
+	for _, t := range f.tokens {
+		if t.UserID == userID {
+			t.Revoked = true
+		}
+	}
+	return nil
+}
+
+type fakeSessions struct {
+	ports.SessionRepository
+	sessions map[string]*domain.Session
+}
+
+func (f *fakeSessions) Save(ctx context.Context, session *domain.Session) error {
+	copied := *session
+	f.sessions[session.ID] = &copied
+	return nil
+}
+
+func (f *fakeSessions) Delete(ctx context.Context, id string) error {
+	delete(f.sessions, id)
+	return nil
+}
+
+type discardPublisher struct{}
+
+func (discardPublisher) Publish(ctx context.Context, event *domain.AuthEvent) error { return nil }
+
+func newRefreshTestService(t *testing.T, user *domain.User) (*AuthService, *fakeRefreshTokens, *fakeSessions) {
+	t.Helper()
+	keys, err := NewHMACKeySet("test-secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	refreshTokens := &fakeRefreshTokens{tokens: map[string]*domain.RefreshToken{}}
+	sessions := &fakeSessions{sessions: map[string]*domain.Session{}}
+	s := NewAuthService(&fakeUsers{users: map[string]*domain.User{user.ID: user}}, refreshTokens, nil, sessions,
+		nil, discardPublisher{}, keys, nil, nil, AuthConfig{AccessExpiry: time.Minute, RefreshExpiry: time.Hour})
+	return s, refreshTokens, sessions
+}
+
+func TestRefreshRotatesToken(t *testing.T) {
+	user := &domain.User{ID: "user-1", Email: "user@example.com", Role: domain.RoleUser, Verified: true}
+	s, refreshTokens, sessions := newRefreshTestService(t, user)
+	ctx := context.Background()
+
+	first, err := s.issueTokens(ctx, user, "family-1", "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := s.Refresh(ctx, first.RefreshToken, "", "")
+	if err != nil {
+		t.Fatalf("Refresh: %v", err)
+	}
+	if second.RefreshToken == first.RefreshToken {
+		t.Error("refresh token was not rotated")
+	}
+
+	old, _ := refreshTokens.FindByHash(hashToken(first.RefreshToken))
+	next, _ := refreshTokens.FindByHash(hashToken(second.RefreshToken))
+	if old.UsedAt == nil || old.ReplacedBy != next.ID {
+		t.Errorf("old token: used_at %v, replaced_by %q; want used and replaced by %q", old.UsedAt, old.ReplacedBy, next.ID)
+	}
+	if next.FamilyID != "family-1" || next.Revoked {
+		t.Errorf("new token: family %q, revoked %v", next.FamilyID, next.Revoked)
+	}
+	if _, ok := sessions.sessions["family-1"]; !ok {
+		t.Error("session missing after refresh")
+	}
+}
+
+func TestRefreshReuseDetection(t *testing.T) {
+	tests := []struct {
+		name string
+		// prepare bringt das erste Refresh-Token in den zu prüfenden Zustand
+		prepare       func(t *testing.T, s *AuthService, refreshTokens *fakeRefreshTokens, token string)
+		wantErr       error
+		wantRevoked   bool
+		wantNoSession bool
+	}{
+		{
+			name: "already used",
+			prepare: func(t *testing.T, s *AuthService, _ *fakeRefreshTokens, token string) {
+				if _, err := s.Refresh(context.Background(), token, "", ""); err != nil {
+					t.Fatal(err)
+				}
+			},
+			wantErr:       ErrRefreshTokenReused,
+			wantRevoked:   true,
+			wantNoSession: true,
+		},
+		{
+			name: "revoked",
+			prepare: func(t *testing.T, _ *AuthService, refreshTokens *fakeRefreshTokens, _ string) {
+				refreshTokens.RevokeFamily("family-1")
+			},
+			wantErr:       ErrRefreshTokenReused,
+			wantRevoked:   true,
+			wantNoSession: true,
+		},
+		{
+			name: "lost race against parallel refresh",
+			prepare: func(t *testing.T, _ *AuthService, refreshTokens *fakeRefreshTokens, _ string) {
+				refreshTokens.loseRace = true
+			},
+			wantErr:       ErrRefreshTokenReused,
+			wantRevoked:   true,
+			wantNoSession: true,
+		},
+		{
+			name: "expired",
+			prepare: func(t *testing.T, _ *AuthService, refreshTokens *fakeRefreshTokens, token string) {
+				stored, _ := refreshTokens.FindByHash(hashToken(token))
+				refreshTokens.tokens[stored.ID].ExpiresAt = time.Now().Add(-time.Second)
+			},
+			wantErr: ErrInvalidRefreshToken,
+		},
+		{
+			name: "unknown",
+			prepare: func(t *testing.T, _ *AuthService, refreshTokens *fakeRefreshTokens, token string) {
+				stored, _ := refreshTokens.FindByHash(hashToken(token))
+				refreshTokens.tokens[stored.ID].TokenHash = "other"
+			},
+			wantErr: ErrInvalidRefreshToken,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			user := &domain.User{ID: "user-1", Email: "user@example.com", Role: domain.RoleUser, Verified: true}
+			s, refreshTokens, sessions := newRefreshTestService(t, user)
+			ctx := context.Background()
+
+			pair, err := s.issueTokens(ctx, user, "family-1", "", "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			tc.prepare(t, s, refreshTokens, pair.RefreshToken)
+
+			if _, err := s.Refresh(ctx, pair.RefreshToken, "", ""); !errors.Is(err, tc.wantErr) {
+				t.Fatalf("Refresh: err = %v, want %v", err, tc.wantErr)
+			}
+			// Bei Wiederverwendung ist die ganze Familie gesperrt, auch ein
+			// zwischenzeitlich ausgestelltes Nachfolge-Token
+			for _, token := range refreshTokens.tokens {
+				if token.Revoked != tc.wantRevoked {
+					t.Errorf("token %s: revoked = %v, want %v", token.ID, token.Revoked, tc.wantRevoked)
+				}
+			}
+			if _, ok := sessions.sessions["family-1"]; ok == tc.wantNoSession {
+				t.Errorf("session present = %v, want %v", ok, !tc.wantNoSession)
+			}
+		})
+	}
+}
+
+func TestRefreshRejectsBlockedUsers(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*domain.User)
+	}{
+		{"disabled", func(u *domain.User) { u.Disabled = true }},
+		{"password reset required", func(u *domain.User) { u.PasswordResetRequired = true }},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			user := &domain.User{ID: "user-1", Email: "user@example.com", Role: domain.RoleUser, Verified: true}
+			s, _, _ := newRefreshTestService(t, user)
+			ctx := context.Background()
+
+			pair, err := s.issueTokens(ctx, user, "family-1", "", "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			tc.modify(user)
+			if _, err := s.Refresh(ctx, pair.RefreshToken, "", ""); !errors.Is(err, ErrInvalidRefreshToken) {
+				t.Errorf("Refresh: err = %v, want ErrInvalidRefreshToken", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package service
+
+import (
+	"auth-service/internal/domain"
+	"context"
+	"math"
+	"time"
+)
+
+// Logout widerruft das vorgelegte Access-Token und beendet seine Sitzung bzw.
+// die des mitgeschickten Refresh-Tokens. Mit all werden alle Tokens des Users
+// gesperrt. Andere Services erfahren davon über das auth-events Topic.
+func (s *AuthService) Logout(ctx context.Context, claims *domain.Claims, refreshToken string, all bool, ip string) error {
+	if all {
+		return s.RevokeAllTokens(ctx, claims.UserID, claims.UserID, ip)
+	}
+
+	sessionID := claims.SessionID
+	if refreshToken != "" {
+		stored, err := s.refreshTokens.FindByHash(hashToken(refreshToken))
+		if err != nil || stored.UserID != claims.UserID {
+			return ErrInvalidRefreshToken
+		}
+		sessionID = stored.FamilyID
+	}
+	if sessionID != "" {
+		if err := s.endSession(ctx, sessionID); err != nil {
+			return err
+		}
+	}
+
+	// Tokens ohne jti stammen von vor dem Update und laufen von selbst ab
+	if claims.ID == "" {
+		return nil
+	}
+	revoked := &domain.RevokedToken{
+		JTI:       claims.ID,
+		UserID:    claims.UserID,
+		ExpiresAt: claims.ExpiresAt,
+		RevokedAt: time.Now(),
+	}
+	if err := s.revocations.RevokeToken(revoked); err != nil {
+		return err
+	}
+	s.emit(&domain.AuthEvent{
+		EventType: domain.EventTokenRevoked,
+		UserID:    claims.UserID,
+		ActorID:   claims.UserID,
+		IP:        ip,
+		Data: map[string]interface{}{
+			"jti":        revoked.JTI,
+			"expires_at": revoked.ExpiresAt,
+		},
+	})
+	return nil
+}
+
+// RevokeAllTokens sperrt alle bisher ausgestellten Access- und Refresh-Tokens
+// eines Users, z.B. bei Verdacht auf ein kompromittiertes Konto.
+func (s *AuthService) RevokeAllTokens(ctx context.Context, userID, actorID, ip string) error {
+	if _, err := s.repo.FindByID(ctx, userID); err != nil {
+		return ErrUserNotFound
+	}
+	if err := s.refreshTokens.RevokeAllForUser(userID); err != nil {
+		return err
+	}
+	if err := s.sessions.DeleteAllForUser(ctx, userID); err != nil {
+		return err
+	}
+
+	// Gleiche Genauigkeit wie iat (siehe issuedAtClaim); MongoDB speichert
+	// ohnehin nur Millisekunden. Ein direkt folgender Login bleibt gültig.
+	now := time.Now().Truncate(time.Millisecond)
+	revocation := &domain.UserTokenRevocation{
+		UserID:        userID,
+		RevokedBefore: now,
+		ExpiresAt:     now.Add(s.cfg.AccessExpiry),
+	}
+	if err := s.revocations.RevokeUserTokens(revocation); err != nil {
+		return err
+	}
+	s.emit(&domain.AuthEvent{
+		EventType: domain.EventUserTokensRevoked,
+		UserID:    userID,
+		ActorID:   actorID,
+		IP:        ip,
+		Data: map[string]interface{}{
+			"revoked_before": revocation.RevokedBefore,
+			"expires_at":     revocation.ExpiresAt,
+		},
+	})
+	return nil
+}
+
+// issuedAtValue ist der iat-Claim für Tokens, die Widerrufe prüfen müssen.
+func issuedAtValue(t time.Time) float64 {
+	return float64(t.UnixMilli()) / 1000
+}
+
+// issuedAtClaim liest iat auf die Millisekunde genau. Access-Tokens tragen iat
+// mit Nachkommastellen, damit ein Widerruf auch Tokens aus derselben Sekunde
+// erfasst; jwt.NumericDate würde beim Parsen auf Sekunden kürzen.
+func issuedAtClaim(v interface{}) time.Time {
+	seconds, ok := v.(float64)
+	if !ok {
+		return time.Time{}
+	}
+	return time.UnixMilli(int64(math.Round(seconds * 1000)))
+}
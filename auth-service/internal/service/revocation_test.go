@@ -0,0 +1,108 @@
+package service
+
+import (
+	"auth-service/internal/domain"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestIssuedAtClaimRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		at   time.Time
+	}{
+		{"full second", time.Unix(1700000000, 0)},
+		{"first millisecond", time.Unix(1700000000, int64(time.Millisecond))},
+		{"last millisecond", time.Unix(1700000000, int64(999*time.Millisecond))},
+		{"sub-millisecond is truncated", time.Unix(1700000000, int64(123*time.Millisecond+999*time.Microsecond))},
+		{"far future", time.Unix(32503680000, int64(7*time.Millisecond))},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			// Der Claim reist als JSON-Zahl und kommt als float64 zurück
+			raw, err := json.Marshal(issuedAtValue(tc.at))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var claim interface{}
+			if err := json.Unmarshal(raw, &claim); err != nil {
+				t.Fatal(err)
+			}
+
+			want := tc.at.Truncate(time.Millisecond)
+			if got := issuedAtClaim(claim); !got.Equal(want) {
+				t.Errorf("issuedAtClaim(%s) = %s, want %s", raw, got.Format(time.RFC3339Nano), want.Format(time.RFC3339Nano))
+			}
+		})
+	}
+}
+
+func TestIssuedAtClaimInvalid(t *testing.T) {
+	for _, v := range []interface{}{nil, "1700000000", json.Number("1700000000"), int64(1700000000)} {
+		if got := issuedAtClaim(v); !got.IsZero() {
+			t.Errorf("issuedAtClaim(%#v) = %s, want zero time", v, got)
+		}
+	}
+}
+
+// fakeRevocations vergleicht wie das Mongo-Repository: widerrufen ist, was
+// vor revokedBefore ausgestellt wurde.
+type fakeRevocations struct {
+	revokedBefore time.Time
+}
+
+func (f *fakeRevocations) RevokeToken(token *domain.RevokedToken) error { return nil }
+
+func (f *fakeRevocations) RevokeUserTokens(revocation *domain.UserTokenRevocation) error {
+	f.revokedBefore = revocation.RevokedBefore
+	return nil
+}
+
+func (f *fakeRevocations) IsRevoked(jti, userID string, issuedAt time.Time) (bool, error) {
+	return f.revokedBefore.After(issuedAt), nil
+}
+
+func TestRevocationMillisecondCutoff(t *testing.T) {
+	keys, err := NewHMACKeySet("test-secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	revocations := &fakeRevocations{}
+	s := NewAuthService(nil, nil, revocations, nil, nil, discardPublisher{}, keys, nil, nil, AuthConfig{AccessExpiry: time.Minute})
+	user := &domain.User{ID: "user-1", Email: "user@example.com", Role: domain.RoleUser, Verified: true}
+
+	token, err := s.signAccessToken(user, domain.TokenTypeAccess)
+	if err != nil {
+		t.Fatal(err)
+	}
+	claims, err := s.ValidateAccessToken(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	issuedAt := claims.IssuedAt
+	if !issuedAt.Equal(issuedAt.Truncate(time.Millisecond)) || issuedAt.IsZero() {
+		t.Fatalf("iat %s does not have millisecond precision", issuedAt.Format(time.RFC3339Nano))
+	}
+
+	tests := []struct {
+		name        string
+		offset      time.Duration
+		wantRevoked bool
+	}{
+		// Ein Login direkt nach dem Widerruf, in derselben Millisekunde
+		{"revoked in the same millisecond", 0, false},
+		{"revoked one millisecond later", time.Millisecond, true},
+		{"revoked within the same second", 999 * time.Millisecond, true},
+		{"revoked one millisecond earlier", -time.Millisecond, false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			revocations.revokedBefore = issuedAt.Add(tc.offset)
+			_, err := s.ValidateAccessToken(token)
+			if revoked := err == ErrInvalidToken; revoked != tc.wantRevoked || (err != nil && !revoked) {
+				t.Errorf("ValidateAccessToken: err = %v, want revoked = %v", err, tc.wantRevoked)
+			}
+		})
+	}
+}
@@ -0,0 +1,146 @@
+package service
+
+import (
+	"auth-service/internal/domain"
+	"auth-service/internal/ports"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+)
+
+var (
+	ErrInvalidRole    = errors.New("invalid role")
+	ErrUserNotFound   = errors.New("user not found")
+	ErrSelfRoleChange = errors.New("admins cannot change their own role")
+)
+
+// BootstrapActor steht in der Historie für Änderungen durch die Startkonfiguration.
+const BootstrapActor = "bootstrap"
+
+type RoleService struct {
+	users   ports.UserRepository
+	changes ports.RoleChangeRepository
+	hasher  PasswordHasher
+	events  ports.EventPublisher
+	auth    *AuthService
+}
+
+func NewRoleService(users ports.UserRepository, changes ports.RoleChangeRepository, hasher PasswordHasher, events ports.EventPublisher, auth *AuthService) *RoleService {
+	return &RoleService{users: users, changes: changes, hasher: hasher, events: events, auth: auth}
+}
+
+// GrantRole setzt die Rolle eines Users und protokolliert die Änderung.
+func (s *RoleService) GrantRole(ctx context.Context, actorID, userID, role, ip string) (*domain.User, error) {
+	if !domain.ValidRole(role) {
+		return nil, ErrInvalidRole
+	}
+	if actorID == userID {
+		return nil, ErrSelfRoleChange
+	}
+	return s.setRole(ctx, actorID, userID, role, ip)
+}
+
+// RevokeRole setzt einen User auf die Standardrolle zurück.
+func (s *RoleService) RevokeRole(ctx context.Context, actorID, userID, ip string) (*domain.User, error) {
+	if actorID == userID {
+		return nil, ErrSelfRoleChange
+	}
+	return s.setRole(ctx, actorID, userID, domain.RoleUser, ip)
+}
+
+func (s *RoleService) History(userID string) ([]domain.RoleChange, error) {
+	return s.changes.FindByUser(userID)
+}
+
+// BootstrapAdmin stellt sicher, dass der konfigurierte erste Admin existiert.
+// Ein vorhandenes Konto wird befördert, sonst wird es neu angelegt; das
+// Passwort muss dann die Passwort-Richtlinie erfüllen.
+func (s *RoleService) BootstrapAdmin(ctx context.Context, email, password string) error {
+	user, err := s.users.FindByEmail(ctx, email)
+	if err == nil {
+		if user.Role == domain.RoleAdmin {
+			return nil
+		}
+		_, err = s.setRole(ctx, BootstrapActor, user.ID, domain.RoleAdmin, "")
+		return err
+	}
+
+	if password == "" {
+		return errors.New("bootstrap admin password required")
+	}
+	if err := s.auth.policy.Check(password); err != nil {
+		return fmt.Errorf("bootstrap admin password rejected: %w", err)
+	}
+	hash, err := s.hasher.Hash(password)
+	if err != nil {
+		return err
+	}
+	user = &domain.User{Email: email, Password: hash, Role: domain.RoleAdmin, Verified: true}
+	if err := s.users.Create(ctx, user); err != nil {
+		return err
+	}
+	log.Printf("Bootstrapped admin account %s", email)
+	publishAsync(s.events, &domain.AuthEvent{
+		EventType: domain.EventUserRegistered,
+		UserID:    user.ID,
+		Email:     user.Email,
+		ActorID:   BootstrapActor,
+	})
+	return s.record(BootstrapActor, user.ID, "", domain.RoleAdmin)
+}
+
+// setRole ändert die Rolle und widerruft alle Tokens des Users, da Rolle und
+// Berechtigungen als Claims im Token stehen und sonst bis zum Ablauf gelten.
+// Ist die Rolle gespeichert, wird die Änderung in jedem Fall protokolliert; ein
+// fehlgeschlagener Widerruf wird nur geloggt, da ein Fehler hier die bereits
+// geänderte Rolle nicht zurücknehmen würde.
+func (s *RoleService) setRole(ctx context.Context, actorID, userID, role, ip string) (*domain.User, error) {
+	user, err := s.users.FindByID(ctx, userID)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+	if user.Role == role {
+		return user, nil
+	}
+
+	if err := s.users.UpdateRole(ctx, userID, role); err != nil {
+		return nil, err
+	}
+	recordErr := s.record(actorID, userID, user.Role, role)
+	if err := s.auth.RevokeAllTokens(ctx, userID, actorID, ip); err != nil {
+		log.Printf("Failed to revoke tokens of %s after role change to %s: %v", userID, role, err)
+	}
+	if recordErr != nil {
+		return nil, recordErr
+	}
+
+	user.Role = role
+	return user, nil
+}
+
+func (s *RoleService) record(actorID, userID, oldRole, newRole string) error {
+	now := time.Now()
+	err := s.changes.Create(&domain.RoleChange{
+		UserID:    userID,
+		OldRole:   oldRole,
+		NewRole:   newRole,
+		ChangedBy: actorID,
+		ChangedAt: now,
+	})
+	if err != nil {
+		return err
+	}
+	publishAsync(s.events, &domain.AuthEvent{
+		EventType: domain.EventRoleChanged,
+		UserID:    userID,
+		ActorID:   actorID,
+		Data: map[string]interface{}{
+			"old_role": oldRole,
+			"new_role": newRole,
+		},
+		Timestamp: now,
+	})
+	return nil
+}
@@ -0,0 +1,84 @@
+package service
+
+import (
+	"auth-service/internal/domain"
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"time"
+)
+
+var ErrSessionNotFound = errors.New("session not found")
+
+const maxUserAgentLength = 256
+
+// ListSessions liefert die aktiven Sitzungen eines Users. currentSessionID
+// (sid des Aufrufers) wird als current markiert.
+func (s *AuthService) ListSessions(ctx context.Context, userID, currentSessionID string) ([]domain.Session, error) {
+	sessions, err := s.sessions.FindByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range sessions {
+		sessions[i].Current = sessions[i].ID == currentSessionID
+	}
+	return sessions, nil
+}
+
+// RevokeSession meldet ein einzelnes Gerät ab: Refresh-Tokens der Sitzung
+// werden widerrufen und das zuletzt ausgestellte Access-Token gesperrt.
+func (s *AuthService) RevokeSession(ctx context.Context, userID, sessionID, actorID, ip string) error {
+	session, err := s.sessions.FindByID(ctx, sessionID)
+	if err != nil || session.UserID != userID {
+		return ErrSessionNotFound
+	}
+	if err := s.endSession(ctx, session.ID); err != nil {
+		return err
+	}
+
+	if session.AccessJTI == "" || !session.AccessExpiresAt.After(time.Now()) {
+		return nil
+	}
+	revoked := &domain.RevokedToken{
+		JTI:       session.AccessJTI,
+		UserID:    userID,
+		ExpiresAt: session.AccessExpiresAt,
+		RevokedAt: time.Now(),
+	}
+	if err := s.revocations.RevokeToken(revoked); err != nil {
+		return err
+	}
+	s.emit(&domain.AuthEvent{
+		EventType: domain.EventTokenRevoked,
+		UserID:    userID,
+		ActorID:   actorID,
+		IP:        ip,
+		Data: map[string]interface{}{
+			"jti":        revoked.JTI,
+			"expires_at": revoked.ExpiresAt,
+			"session_id": session.ID,
+		},
+	})
+	return nil
+}
+
+// endSession widerruft die Refresh-Token-Familie und entfernt die Sitzung.
+func (s *AuthService) endSession(ctx context.Context, sessionID string) error {
+	if err := s.refreshTokens.RevokeFamily(sessionID); err != nil {
+		return err
+	}
+	// Familien von vor Einführung der Sitzungen haben keinen Eintrag
+	if err := s.sessions.Delete(ctx, sessionID); err != nil {
+		log.Printf("No session record for family %s: %v", sessionID, err)
+	}
+	return nil
+}
+
+func truncateUserAgent(userAgent string) string {
+	if len(userAgent) <= maxUserAgentLength {
+		return userAgent
+	}
+	// Ein angeschnittenes Zeichen am Ende wird entfernt
+	return strings.ToValidUTF8(userAgent[:maxUserAgentLength], "")
+}
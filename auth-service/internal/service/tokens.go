@@ -0,0 +1,34 @@
+package service
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+)
+
+// randomToken erzeugt ein zufälliges, URL-sicheres Token mit n Bytes Entropie.
+func randomToken(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// newID erzeugt eine zufällige ID für Dokumente, deren ID vor dem Speichern
+// bekannt sein muss.
+func newID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// hashToken speichert Tokens nur als SHA-256, damit ein Datenbank-Leak keine
+// gültigen Tokens preisgibt.
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
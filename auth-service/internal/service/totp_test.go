@@ -0,0 +1,92 @@
+package service
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// Schlüssel "12345678901234567890" aus RFC 6238, Anhang B, Base32-kodiert
+const rfc6238Secret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+// Testvektoren aus RFC 6238, Anhang B (SHA1), auf 6 Stellen gekürzt
+var rfc6238Vectors = []struct {
+	unix int64
+	code string
+}{
+	{59, "287082"},
+	{1111111109, "081804"},
+	{1111111111, "050471"},
+	{1234567890, "005924"},
+	{2000000000, "279037"},
+	{20000000000, "353130"},
+}
+
+func TestTOTPCodeRFC6238(t *testing.T) {
+	for _, tc := range rfc6238Vectors {
+		got, err := totpCode(rfc6238Secret, tc.unix/totpPeriod)
+		if err != nil {
+			t.Fatalf("totpCode(%d): %v", tc.unix, err)
+		}
+		if got != tc.code {
+			t.Errorf("totpCode(%d) = %s, want %s", tc.unix, got, tc.code)
+		}
+	}
+}
+
+func TestValidateTOTP(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+	current := now.Unix() / totpPeriod
+	codeAt := func(step int64) string {
+		code, err := totpCode(rfc6238Secret, current+step)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return code
+	}
+
+	tests := []struct {
+		name     string
+		code     string
+		wantStep int64
+		wantOK   bool
+	}{
+		{"current step", codeAt(0), current, true},
+		{"previous step within skew", codeAt(-1), current - 1, true},
+		{"next step within skew", codeAt(1), current + 1, true},
+		{"surrounding whitespace", " " + codeAt(0) + "\n", current, true},
+		{"outside skew", codeAt(-2), 0, false},
+		{"wrong code", "000000", 0, false},
+		{"too short", codeAt(0)[:5], 0, false},
+		{"empty", "", 0, false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			step, ok := validateTOTP(rfc6238Secret, tc.code, now)
+			if ok != tc.wantOK || step != tc.wantStep {
+				t.Errorf("validateTOTP(%q) = (%d, %v), want (%d, %v)", tc.code, step, ok, tc.wantStep, tc.wantOK)
+			}
+		})
+	}
+}
+
+func TestValidateTOTPLowercaseSecret(t *testing.T) {
+	now := time.Unix(59, 0)
+	if _, ok := validateTOTP(strings.ToLower(rfc6238Secret), "287082", now); !ok {
+		t.Error("lowercase secret rejected")
+	}
+}
+
+func TestGenerateTOTPSecret(t *testing.T) {
+	secret, err := generateTOTPSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := base32NoPad.DecodeString(secret)
+	if err != nil {
+		t.Fatalf("secret %q is not base32: %v", secret, err)
+	}
+	if len(key) != 20 {
+		t.Errorf("key length = %d, want 20", len(key))
+	}
+}
@@ -0,0 +1,129 @@
+package service
+
+import (
+	"auth-service/internal/domain"
+	"auth-service/internal/ports"
+	"context"
+	"errors"
+	"log"
+)
+
+var (
+	ErrSelfAccountChange = errors.New("admins cannot disable or delete their own account")
+	ErrInvalidUserFilter = errors.New("invalid user filter")
+)
+
+const (
+	defaultUserPageSize = 20
+	maxUserPageSize     = 100
+)
+
+// UserService bündelt die Support-Funktionen für Admins.
+type UserService struct {
+	users     ports.UserRepository
+	auth      *AuthService
+	passwords *PasswordService
+}
+
+func NewUserService(users ports.UserRepository, auth *AuthService, passwords *PasswordService) *UserService {
+	return &UserService{users: users, auth: auth, passwords: passwords}
+}
+
+func (s *UserService) ListUsers(ctx context.Context, filter domain.UserFilter) (*domain.UserPage, error) {
+	if filter.Role != "" && !domain.ValidRole(filter.Role) {
+		return nil, ErrInvalidUserFilter
+	}
+	if filter.Status != "" && !domain.ValidUserStatus(filter.Status) {
+		return nil, ErrInvalidUserFilter
+	}
+	if filter.Page < 1 {
+		filter.Page = 1
+	}
+	if filter.PageSize < 1 {
+		filter.PageSize = defaultUserPageSize
+	}
+	if filter.PageSize > maxUserPageSize {
+		filter.PageSize = maxUserPageSize
+	}
+
+	users, total, err := s.users.List(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.UserPage{Users: users, Total: total, Page: filter.Page, PageSize: filter.PageSize}, nil
+}
+
+func (s *UserService) GetUser(ctx context.Context, userID string) (*domain.User, error) {
+	user, err := s.users.FindByID(ctx, userID)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+	return user, nil
+}
+
+// DisableUser sperrt das Konto dauerhaft und widerruft alle Tokens.
+func (s *UserService) DisableUser(ctx context.Context, actorID, userID, ip string) (*domain.User, error) {
+	if actorID == userID {
+		return nil, ErrSelfAccountChange
+	}
+	if err := s.users.SetDisabled(ctx, userID, true); err != nil {
+		return nil, ErrUserNotFound
+	}
+	if err := s.auth.RevokeAllTokens(ctx, userID, actorID, ip); err != nil {
+		return nil, err
+	}
+	log.Printf("User %s disabled by %s", userID, actorID)
+	return s.GetUser(ctx, userID)
+}
+
+func (s *UserService) EnableUser(ctx context.Context, actorID, userID string) (*domain.User, error) {
+	if err := s.users.SetDisabled(ctx, userID, false); err != nil {
+		return nil, ErrUserNotFound
+	}
+	log.Printf("User %s enabled by %s", userID, actorID)
+	return s.GetUser(ctx, userID)
+}
+
+// ForcePasswordReset meldet den User überall ab und verschickt einen
+// Reset-Link. Bis das Passwort neu gesetzt ist, ist kein Login möglich.
+func (s *UserService) ForcePasswordReset(ctx context.Context, actorID, userID, ip string) error {
+	user, err := s.users.FindByID(ctx, userID)
+	if err != nil {
+		return ErrUserNotFound
+	}
+	if err := s.users.RequirePasswordReset(ctx, userID); err != nil {
+		return err
+	}
+	if err := s.auth.RevokeAllTokens(ctx, userID, actorID, ip); err != nil {
+		return err
+	}
+	log.Printf("Password reset for user %s forced by %s", userID, actorID)
+	return s.passwords.SendResetLink(user)
+}
+
+// DeleteUser löscht das Konto. Tokens werden vorher widerrufen, damit sie
+// nicht bis zum Ablauf weiter gelten.
+func (s *UserService) DeleteUser(ctx context.Context, actorID, userID, ip string) error {
+	if actorID == userID {
+		return ErrSelfAccountChange
+	}
+	user, err := s.users.FindByID(ctx, userID)
+	if err != nil {
+		return ErrUserNotFound
+	}
+	if err := s.auth.RevokeAllTokens(ctx, userID, actorID, ip); err != nil {
+		return err
+	}
+	if err := s.users.Delete(ctx, userID); err != nil {
+		return ErrUserNotFound
+	}
+	log.Printf("User %s deleted by %s", userID, actorID)
+	s.auth.emit(&domain.AuthEvent{
+		EventType: domain.EventUserDeleted,
+		UserID:    user.ID,
+		Email:     user.Email,
+		ActorID:   actorID,
+		IP:        ip,
+	})
+	return nil
+}
@@ -0,0 +1,93 @@
+package service
+
+import (
+	"auth-service/internal/domain"
+	"auth-service/internal/ports"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"time"
+)
+
+var ErrInvalidVerificationToken = errors.New("invalid or expired verification token")
+
+type VerificationService struct {
+	users          ports.UserRepository
+	tokens         ports.OneTimeTokenRepository
+	mailer         ports.Mailer
+	expiry         time.Duration
+	resendInterval time.Duration
+	baseURL        string
+}
+
+func NewVerificationService(users ports.UserRepository, tokens ports.OneTimeTokenRepository, mailer ports.Mailer, expirySeconds, resendIntervalSeconds int, baseURL string) *VerificationService {
+	return &VerificationService{
+		users:          users,
+		tokens:         tokens,
+		mailer:         mailer,
+		expiry:         time.Duration(expirySeconds) * time.Second,
+		resendInterval: time.Duration(resendIntervalSeconds) * time.Second,
+		baseURL:        baseURL,
+	}
+}
+
+// SendVerification verschickt einen neuen Bestätigungslink. Ältere Links
+// werden dabei ungültig.
+func (s *VerificationService) SendVerification(user *domain.User) error {
+	if err := s.tokens.InvalidateForUser(user.ID, domain.TokenPurposeEmailVerification); err != nil {
+		return err
+	}
+
+	token, err := randomToken(32)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	err = s.tokens.Create(&domain.OneTimeToken{
+		UserID:    user.ID,
+		Purpose:   domain.TokenPurposeEmailVerification,
+		TokenHash: hashToken(token),
+		ExpiresAt: now.Add(s.expiry),
+		CreatedAt: now,
+	})
+	if err != nil {
+		return err
+	}
+
+	link := fmt.Sprintf("%s/verify-email?token=%s", s.baseURL, url.QueryEscape(token))
+	return s.mailer.Send(&domain.Mail{
+		To:      user.Email,
+		Subject: "Bitte bestätige deine E-Mail-Adresse",
+		Body: fmt.Sprintf("Hallo,\n\nbitte bestätige deine E-Mail-Adresse über diesen Link:\n\n%s\n\n"+
+			"Der Link ist %d Stunden gültig.\n", link, int(s.expiry.Hours())),
+	})
+}
+
+func (s *VerificationService) Verify(ctx context.Context, token string) error {
+	stored, err := s.tokens.Consume(hashToken(token), domain.TokenPurposeEmailVerification)
+	if err != nil {
+		return ErrInvalidVerificationToken
+	}
+	return s.users.MarkVerified(ctx, stored.UserID)
+}
+
+// Resend verschickt den Bestätigungslink erneut, höchstens einmal pro
+// resendInterval. Unbekannte, bereits bestätigte und gedrosselte Adressen
+// werden stillschweigend ignoriert, damit der Endpoint keinen Kontostatus
+// verrät.
+func (s *VerificationService) Resend(ctx context.Context, email string) error {
+	user, err := s.users.FindByEmail(ctx, email)
+	if err != nil || user.Verified {
+		return nil
+	}
+
+	if last, err := s.tokens.LatestForUser(user.ID, domain.TokenPurposeEmailVerification); err == nil {
+		if time.Since(last.CreatedAt) < s.resendInterval {
+			log.Printf("Verification resend for user %s throttled", user.ID)
+			return nil
+		}
+	}
+	return s.SendVerification(user)
+}
@@ -0,0 +1,171 @@
+// Package introspection ist ein kleiner Client für den /introspect Endpunkt des
+// auth-service. Er hat außer der Standardbibliothek keine Abhängigkeiten und
+// kann von anderen Services importiert oder übernommen werden.
+package introspection
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrInactive wird von Validate zurückgegeben, wenn das Token nicht aktiv ist.
+var ErrInactive = errors.New("token is not active")
+
+// Result entspricht der Antwort des auth-service.
+type Result struct {
+	Active      bool     `json:"active"`
+	Subject     string   `json:"sub,omitempty"`
+	UserID      string   `json:"user_id,omitempty"`
+	Email       string   `json:"email,omitempty"`
+	Role        string   `json:"role,omitempty"`
+	Permissions []string `json:"permissions,omitempty"`
+	Type        string   `json:"typ,omitempty"`
+	JTI         string   `json:"jti,omitempty"`
+	IssuedAt    int64    `json:"iat,omitempty"`
+	ExpiresAt   int64    `json:"exp,omitempty"`
+}
+
+// HasPermission prüft eine Berechtigung aus dem Claim "permissions".
+func (r *Result) HasPermission(permission string) bool {
+	for _, p := range r.Permissions {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+type Config struct {
+	// URL des Endpunkts, z.B. http://auth-service:8081/introspect
+	URL          string
+	ClientID     string
+	ClientSecret string
+	// CacheTTL begrenzt, wie lange ein Ergebnis wiederverwendet wird. Ein
+	// widerrufenes Token kann so lange noch als aktiv gelten. Standard 30s,
+	// negative Werte schalten den Cache ab.
+	CacheTTL   time.Duration
+	HTTPClient *http.Client
+}
+
+type cacheEntry struct {
+	result    *Result
+	expiresAt time.Time
+}
+
+type Client struct {
+	cfg Config
+
+	mu    sync.Mutex
+	cache map[string]cacheEntry
+}
+
+func NewClient(cfg Config) *Client {
+	if cfg.CacheTTL == 0 {
+		cfg.CacheTTL = 30 * time.Second
+	}
+	if cfg.HTTPClient == nil {
+		cfg.HTTPClient = &http.Client{Timeout: 5 * time.Second}
+	}
+	return &Client{cfg: cfg, cache: map[string]cacheEntry{}}
+}
+
+// Introspect fragt den Status eines Tokens ab. Ein inaktives Token ist kein
+// Fehler; Fehler bedeuten, dass der auth-service nicht gefragt werden konnte.
+func (c *Client) Introspect(ctx context.Context, token string) (*Result, error) {
+	key := cacheKey(token)
+	if result, ok := c.cached(key); ok {
+		return result, nil
+	}
+
+	form := url.Values{"token": {token}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.cfg.URL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth(c.cfg.ClientID, c.cfg.ClientSecret)
+
+	resp, err := c.cfg.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("introspection failed with status %d", resp.StatusCode)
+	}
+
+	var result Result
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+	c.store(key, &result)
+	return &result, nil
+}
+
+// Validate liefert das Ergebnis nur für aktive Tokens, sonst ErrInactive.
+func (c *Client) Validate(ctx context.Context, token string) (*Result, error) {
+	result, err := c.Introspect(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	if !result.Active {
+		return nil, ErrInactive
+	}
+	return result, nil
+}
+
+func (c *Client) cached(key string) (*Result, bool) {
+	if c.cfg.CacheTTL < 0 {
+		return nil, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.cache[key]
+	if !ok {
+		return nil, false
+	}
+	if time.Now().After(entry.expiresAt) {
+		delete(c.cache, key)
+		return nil, false
+	}
+	return entry.result, true
+}
+
+func (c *Client) store(key string, result *Result) {
+	if c.cfg.CacheTTL < 0 {
+		return
+	}
+	now := time.Now()
+	expiresAt := now.Add(c.cfg.CacheTTL)
+	// nie länger cachen, als das Token selbst gültig ist
+	if result.Active && result.ExpiresAt > 0 {
+		if tokenExp := time.Unix(result.ExpiresAt, 0); tokenExp.Before(expiresAt) {
+			expiresAt = tokenExp
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	// abgelaufene Einträge beim Schreiben aufräumen, damit der Cache nicht wächst
+	for k, entry := range c.cache {
+		if now.After(entry.expiresAt) {
+			delete(c.cache, k)
+		}
+	}
+	c.cache[key] = cacheEntry{result: result, expiresAt: expiresAt}
+}
+
+// Tokens werden nur als Hash im Speicher gehalten.
+func cacheKey(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -13,16 +13,27 @@ type OrderItem struct {
 	TotalPrice float64 `json:"total_price"`
 }
 
+type ShippingAddress struct {
+	Name       string `json:"name"`
+	Street     string `json:"street"`
+	PostalCode string `json:"postal_code"`
+	City       string `json:"city"`
+	Country    string `json:"country"`
+}
+
 type CheckoutRequest struct {
-	EventType    string      `json:"event_type"`
-	OrderID      string      `json:"order_id"`
-	UserID       string      `json:"user_id"`
-	Items        []OrderItem `json:"items"`
-	ProductIDs   []string    `json:"product_ids"`
-	TotalAmount  float64     `json:"total_amount"`
-	Currency     string      `json:"currency"`
-	Status       string      `json:"status"`
-	Timestamp    string      `json:"timestamp"`
+	EventType   string      `json:"event_type"`
+	OrderID     string      `json:"order_id"`
+	UserID      string      `json:"user_id"`
+	Items       []OrderItem `json:"items"`
+	ProductIDs  []string    `json:"product_ids"`
+	TotalAmount float64     `json:"total_amount"`
+	Currency    string      `json:"currency"`
+	Status      string      `json:"status"`
+	Timestamp   string      `json:"timestamp"`
+	// Filled from the user's profile by shopping-service, if available
+	ShippingAddress *ShippingAddress `json:"shipping_address,omitempty"`
+	CustomerName    string           `json:"customer_name,omitempty"`
 }
 
 func (c *CheckoutRequest) Validate() error {
@@ -60,5 +71,13 @@ func (c *CheckoutRequest) ToOrder() *Order {
 	// Default payment provider - could be configurable
 	paymentProvider := "stripe"
 
-	return NewOrder(userID, productIDs, c.TotalAmount, paymentProvider)
+	order := NewOrder(userID, productIDs, c.TotalAmount, paymentProvider)
+	order.AuthUserID = c.UserID
+	// Keep the order ID from shopping-service, its stock reservation is
+	// committed or released by that ID once the payment completes
+	if id, err := uuid.Parse(c.OrderID); err == nil {
+		order.ID = id
+	}
+	order.ShippingAddress = c.ShippingAddress
+	return order
 }
@@ -7,24 +7,30 @@ import (
 )
 
 type Order struct {
-	ID              uuid.UUID `json:"id"`
-	UserID          uuid.UUID `json:"user_id"`
-	TotalAmount     float64   `json:"total_amount"`
-	Currency        string    `json:"currency"`
-	PaymentProvider string    `json:"payment_provider"`
-	Items           []string  `json:"items"`
-	Status          string    `json:"status"`
-	CreatedAt       time.Time `json:"created_at"`
+	ID     uuid.UUID `json:"id"`
+	UserID uuid.UUID `json:"user_id"`
+	// AuthUserID is the user ID issued by auth-service, which is not a UUID.
+	// payment-service stores it so GDPR requests can find the payments.
+	AuthUserID      string           `json:"auth_user_id,omitempty"`
+	TotalAmount     float64          `json:"total_amount"`
+	Currency        string           `json:"currency"`
+	PaymentProvider string           `json:"payment_provider"`
+	Items           []string         `json:"items"`
+	Status          string           `json:"status"`
+	CreatedAt       time.Time        `json:"created_at"`
+	ShippingAddress *ShippingAddress `json:"shipping_address,omitempty"`
 }
 
 type OrderCreatedEvent struct {
-	OrderID         string    `json:"order_id"`
-	UserID          string    `json:"user_id"`
-	TotalAmount     float64   `json:"total_amount"`
-	Currency        string    `json:"currency"`
-	PaymentProvider string    `json:"payment_provider"`
-	Items           []string  `json:"items"`
-	CreatedAt       time.Time `json:"created_at"`
+	OrderID         string           `json:"order_id"`
+	UserID          string           `json:"user_id"`
+	AuthUserID      string           `json:"auth_user_id,omitempty"`
+	TotalAmount     float64          `json:"total_amount"`
+	Currency        string           `json:"currency"`
+	PaymentProvider string           `json:"payment_provider"`
+	Items           []string         `json:"items"`
+	CreatedAt       time.Time        `json:"created_at"`
+	ShippingAddress *ShippingAddress `json:"shipping_address,omitempty"`
 }
 
 func NewOrder(userID uuid.UUID, items []string, totalAmount float64, paymentProvider string) *Order {
@@ -44,10 +50,12 @@ func (o *Order) ToEvent() *OrderCreatedEvent {
 	return &OrderCreatedEvent{
 		OrderID:         o.ID.String(),
 		UserID:          o.UserID.String(),
+		AuthUserID:      o.AuthUserID,
 		TotalAmount:     o.TotalAmount,
 		Currency:        o.Currency,
 		PaymentProvider: o.PaymentProvider,
 		Items:           o.Items,
 		CreatedAt:       o.CreatedAt,
+		ShippingAddress: o.ShippingAddress,
 	}
 }
@@ -4,6 +4,7 @@ CREATE TABLE payments (
                           id UUID PRIMARY KEY,
                           order_id UUID NOT NULL,
                           user_id UUID NOT NULL,
+                          auth_user_id VARCHAR(64),
                           provider VARCHAR(32) NOT NULL,
                           amount DOUBLE PRECISION NOT NULL,
                           currency VARCHAR(8) NOT NULL,
@@ -16,4 +17,5 @@ CREATE TABLE payments (
 
 CREATE INDEX IF NOT EXISTS idx_payments_order_id ON payments (order_id);
 CREATE INDEX IF NOT EXISTS idx_payments_user_id ON payments (user_id);
+CREATE INDEX IF NOT EXISTS idx_payments_auth_user_id ON payments (auth_user_id);
 CREATE INDEX IF NOT EXISTS idx_payments_status ON payments (status);
\ No newline at end of file
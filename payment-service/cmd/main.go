@@ -11,7 +11,9 @@ import (
 
 	"os/signal"
 	"payment-service/internal/adapters/kafka"
+	"strconv"
 	"syscall"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	gormpostgres "gorm.io/driver/postgres" // Alias für gorm.io/driver/postgres
@@ -51,12 +53,43 @@ func main() {
 		}
 	}()
 
-	r.GET("/payments", handler.GetAllPayments)
-	r.GET("/payments/:id", handler.GetPayment)
-	r.POST("/payments", handler.CreatePayment)
-	r.PUT("/payments/:id/status", handler.UpdatePaymentStatus)
-	r.DELETE("/payments/:id", handler.DeletePayment)
-	r.GET("/payments/search", handler.GetPaymentsByStatus)
+	// GDPR export and erasure requests from auth-service
+	dataRequests := kafka.NewDataRequestConsumer()
+	go func() {
+		if err := dataRequests.StartConsuming(ctx, svc); err != nil {
+			log.Printf("Data request consumer stopped: %v", err)
+		}
+	}()
+
+	// Only machine clients with service tokens from auth-service may call the API
+	verifier, err := http.NewTokenVerifier()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Revoked clients are announced on auth-events; replay one service token
+	// lifetime so revocations survive a restart
+	tokenLifetime := 5 * time.Minute
+	if v, err := strconv.Atoi(os.Getenv("SERVICE_TOKEN_LIFETIME")); err == nil && v > 0 {
+		tokenLifetime = time.Duration(v) * time.Second
+	}
+	revokedClients := http.NewClientDenylist()
+	revocations := kafka.NewClientRevocationsConsumer(revokedClients, tokenLifetime)
+	go func() {
+		if err := revocations.StartConsuming(ctx); err != nil {
+			log.Printf("Client revocation consumer stopped: %v", err)
+		}
+	}()
+
+	canRead := http.RequireScope(verifier, revokedClients, http.ScopePaymentsRead)
+	canWrite := http.RequireScope(verifier, revokedClients, http.ScopePaymentsWrite)
+
+	r.GET("/payments", canRead, handler.GetAllPayments)
+	r.GET("/payments/:id", canRead, handler.GetPayment)
+	r.POST("/payments", canWrite, handler.CreatePayment)
+	r.PUT("/payments/:id/status", canWrite, handler.UpdatePaymentStatus)
+	r.DELETE("/payments/:id", canWrite, handler.DeletePayment)
+	r.GET("/payments/search", canRead, handler.GetPaymentsByStatus)
 
 	port := os.Getenv("PORT")
 	if port == "" {
@@ -0,0 +1,106 @@
+package http
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	jwt "github.com/golang-jwt/jwt/v5"
+)
+
+// Scopes granted to machine clients by auth-service
+const (
+	ScopePaymentsRead  = "payments:read"
+	ScopePaymentsWrite = "payments:write"
+)
+
+const tokenTypeService = "service"
+
+const contextClientIDKey = "client_id"
+
+// TokenVerifier checks the signature of service tokens, either against
+// auth-service's public keys or a shared secret.
+type TokenVerifier struct {
+	keyfunc jwt.Keyfunc
+	algs    []string
+}
+
+// NewTokenVerifier verifies tokens via JWKS_URL if set, like shopping-service;
+// JWT_SECRET (HS256) remains as a fallback for setups without asymmetric
+// signing.
+func NewTokenVerifier() (*TokenVerifier, error) {
+	if url := os.Getenv("JWKS_URL"); url != "" {
+		log.Printf("Verifying service tokens via JWKS from %s", url)
+		return &TokenVerifier{
+			keyfunc: NewJWKSCache(url, 5*time.Minute).Keyfunc,
+			algs:    []string{jwt.SigningMethodRS256.Alg(), jwt.SigningMethodEdDSA.Alg()},
+		}, nil
+	}
+
+	secret := os.Getenv("JWT_SECRET")
+	if secret == "" {
+		return nil, errors.New("JWKS_URL or JWT_SECRET must be set")
+	}
+	return &TokenVerifier{
+		keyfunc: func(t *jwt.Token) (interface{}, error) { return []byte(secret), nil },
+		algs:    []string{jwt.SigningMethodHS256.Alg()},
+	}, nil
+}
+
+// RequireScope accepts only service tokens issued by auth-service via the
+// client_credentials grant that carry the given scope. Tokens of clients
+// revoked in auth-service are rejected even before they expire.
+func RequireScope(verifier *TokenVerifier, revoked *ClientDenylist, scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		auth := c.GetHeader("Authorization")
+		parts := strings.SplitN(auth, " ", 2)
+		if len(parts) != 2 || strings.ToLower(parts[0]) != "bearer" {
+			c.Header("WWW-Authenticate", "Bearer")
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "missing or invalid authorization header"})
+			return
+		}
+
+		claims := jwt.MapClaims{}
+		token, err := jwt.ParseWithClaims(parts[1], claims, verifier.keyfunc,
+			jwt.WithValidMethods(verifier.algs), jwt.WithExpirationRequired(), jwt.WithLeeway(5*time.Second))
+		if err != nil || !token.Valid {
+			c.Header("WWW-Authenticate", `Bearer error="invalid_token"`)
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid token"})
+			return
+		}
+		if typ, _ := claims["typ"].(string); typ != tokenTypeService {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "token type not allowed"})
+			return
+		}
+
+		clientID, _ := claims["client_id"].(string)
+		if revoked.IsRevoked(clientID) {
+			c.Header("WWW-Authenticate", `Bearer error="invalid_token"`)
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "client revoked"})
+			return
+		}
+
+		granted, _ := claims["scope"].(string)
+		if !hasScope(granted, scope) {
+			c.Header("WWW-Authenticate", `Bearer error="insufficient_scope", scope="`+scope+`"`)
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "insufficient scope"})
+			return
+		}
+
+		c.Set(contextClientIDKey, clientID)
+		c.Next()
+	}
+}
+
+func hasScope(granted, scope string) bool {
+	for _, s := range strings.Fields(granted) {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
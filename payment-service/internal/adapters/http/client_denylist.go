@@ -0,0 +1,50 @@
+package http
+
+import (
+	"sync"
+	"time"
+)
+
+// ClientDenylist holds machine clients revoked in auth-service. Their service
+// tokens are rejected until the last one issued before the revocation has
+// expired; a revoked client cannot obtain new tokens.
+type ClientDenylist struct {
+	mu      sync.RWMutex
+	clients map[string]time.Time // client_id -> expires_at
+}
+
+func NewClientDenylist() *ClientDenylist {
+	return &ClientDenylist{clients: map[string]time.Time{}}
+}
+
+// RevokeClient blocks all service tokens of clientID until expiresAt.
+func (d *ClientDenylist) RevokeClient(clientID string, expiresAt time.Time) {
+	if clientID == "" || time.Now().After(expiresAt) {
+		return
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if current, ok := d.clients[clientID]; ok && current.After(expiresAt) {
+		return
+	}
+	d.clients[clientID] = expiresAt
+}
+
+func (d *ClientDenylist) IsRevoked(clientID string) bool {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	_, ok := d.clients[clientID]
+	return ok
+}
+
+// Prune removes clients whose tokens have all expired.
+func (d *ClientDenylist) Prune() {
+	now := time.Now()
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for clientID, exp := range d.clients {
+		if now.After(exp) {
+			delete(d.clients, clientID)
+		}
+	}
+}
@@ -0,0 +1,159 @@
+package http
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+
+	jwt "github.com/golang-jwt/jwt/v5"
+)
+
+// Unknown key IDs trigger a reload, but at most this often.
+const jwksMinRefreshInterval = 10 * time.Second
+
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+}
+
+type jwksKey struct {
+	alg string
+	key interface{}
+}
+
+// JWKSCache loads auth-service's public keys and keeps them for ttl. After a
+// key rotation the first token with a new key ID triggers an immediate reload.
+//
+// A copy of this file lives in
+// shopping-service/internal/adapters/http/middleware/jwks.go; keep both in sync.
+type JWKSCache struct {
+	url    string
+	ttl    time.Duration
+	client *http.Client
+
+	mu          sync.RWMutex
+	keys        map[string]jwksKey
+	fetchedAt   time.Time
+	lastAttempt time.Time
+}
+
+func NewJWKSCache(url string, ttl time.Duration) *JWKSCache {
+	return &JWKSCache{
+		url:    url,
+		ttl:    ttl,
+		client: &http.Client{Timeout: 5 * time.Second},
+		keys:   map[string]jwksKey{},
+	}
+}
+
+// Keyfunc can be passed directly to jwt.Parse.
+func (c *JWKSCache) Keyfunc(t *jwt.Token) (interface{}, error) {
+	kid, _ := t.Header["kid"].(string)
+
+	key, ok, stale := c.lookup(kid)
+	if !ok || stale {
+		if err := c.refresh(!ok); err != nil {
+			log.Printf("JWKS refresh failed: %v", err)
+		}
+		key, ok, _ = c.lookup(kid)
+	}
+	if !ok {
+		return nil, fmt.Errorf("unknown key id %q", kid)
+	}
+	if t.Method.Alg() != key.alg {
+		return nil, jwt.ErrTokenUnverifiable
+	}
+	return key.key, nil
+}
+
+func (c *JWKSCache) lookup(kid string) (jwksKey, bool, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	key, ok := c.keys[kid]
+	return key, ok, time.Since(c.fetchedAt) > c.ttl
+}
+
+func (c *JWKSCache) refresh(force bool) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if time.Since(c.lastAttempt) < jwksMinRefreshInterval {
+		return nil
+	}
+	if !force && time.Since(c.fetchedAt) <= c.ttl {
+		return nil
+	}
+	c.lastAttempt = time.Now()
+
+	resp, err := c.client.Get(c.url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	var body struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return err
+	}
+
+	keys := map[string]jwksKey{}
+	for _, k := range body.Keys {
+		parsed, err := parseJWK(k)
+		if err != nil {
+			log.Printf("Skipping JWK %s: %v", k.Kid, err)
+			continue
+		}
+		keys[k.Kid] = parsed
+	}
+	c.keys = keys
+	c.fetchedAt = time.Now()
+	return nil
+}
+
+func parseJWK(k jwk) (jwksKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return jwksKey{}, err
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return jwksKey{}, err
+		}
+		pub := &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+		return jwksKey{alg: jwt.SigningMethodRS256.Alg(), key: pub}, nil
+	case "OKP":
+		if k.Crv != "Ed25519" {
+			return jwksKey{}, fmt.Errorf("unsupported curve %s", k.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return jwksKey{}, err
+		}
+		if len(x) != ed25519.PublicKeySize {
+			return jwksKey{}, errors.New("invalid Ed25519 key length")
+		}
+		return jwksKey{alg: jwt.SigningMethodEdDSA.Alg(), key: ed25519.PublicKey(x)}, nil
+	default:
+		return jwksKey{}, fmt.Errorf("unsupported key type %s", k.Kty)
+	}
+}
@@ -0,0 +1,132 @@
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"sync"
+	"time"
+
+	"payment-service/internal/adapters/http"
+
+	"github.com/segmentio/kafka-go"
+)
+
+const authEventsTopic = "auth-events"
+
+// replayMargin covers clock skew and the JWT leeway when choosing where to
+// start reading.
+const replayMargin = time.Minute
+
+// ClientRevocationsConsumer fills the client denylist from client_revoked
+// events. Every instance needs every event, so instead of a consumer group
+// each partition is read directly, starting one service token lifetime back.
+type ClientRevocationsConsumer struct {
+	broker        string
+	denylist      *http.ClientDenylist
+	tokenLifetime time.Duration
+}
+
+func NewClientRevocationsConsumer(denylist *http.ClientDenylist, tokenLifetime time.Duration) *ClientRevocationsConsumer {
+	return &ClientRevocationsConsumer{broker: "kafka:9092", denylist: denylist, tokenLifetime: tokenLifetime}
+}
+
+func (c *ClientRevocationsConsumer) StartConsuming(ctx context.Context) error {
+	partitions, err := c.partitions(ctx)
+	if err != nil {
+		return err
+	}
+	log.Printf("Starting to consume client revocations from '%s' topic (%d partitions)", authEventsTopic, len(partitions))
+
+	go c.pruneLoop(ctx)
+
+	var wg sync.WaitGroup
+	for _, p := range partitions {
+		wg.Add(1)
+		go func(partition int) {
+			defer wg.Done()
+			c.consumePartition(ctx, partition)
+		}(p.ID)
+	}
+	wg.Wait()
+	return nil
+}
+
+func (c *ClientRevocationsConsumer) partitions(ctx context.Context) ([]kafka.Partition, error) {
+	var lastErr error
+	// kafka may not be ready yet when the service starts
+	for attempt := 0; attempt < 10; attempt++ {
+		conn, err := kafka.DialContext(ctx, "tcp", c.broker)
+		if err == nil {
+			partitions, err := conn.ReadPartitions(authEventsTopic)
+			conn.Close()
+			if err == nil && len(partitions) > 0 {
+				return partitions, nil
+			}
+			lastErr = err
+		} else {
+			lastErr = err
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(3 * time.Second):
+		}
+	}
+	return nil, lastErr
+}
+
+func (c *ClientRevocationsConsumer) consumePartition(ctx context.Context, partition int) {
+	reader := kafka.NewReader(kafka.ReaderConfig{
+		Brokers:     []string{c.broker},
+		Topic:       authEventsTopic,
+		Partition:   partition,
+		StartOffset: kafka.FirstOffset,
+	})
+	defer reader.Close()
+
+	// fall back to the full topic if the offset lookup fails
+	since := time.Now().Add(-c.tokenLifetime - replayMargin)
+	if err := reader.SetOffsetAt(ctx, since); err != nil {
+		log.Printf("Failed to seek auth-events partition %d to %s, replaying from start: %v", partition, since.Format(time.RFC3339), err)
+	}
+
+	for {
+		message, err := reader.ReadMessage(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			log.Printf("Failed to read auth event: %v", err)
+			continue
+		}
+
+		var event struct {
+			EventType string `json:"event_type"`
+			Data      struct {
+				ClientID  string    `json:"client_id"`
+				ExpiresAt time.Time `json:"expires_at"`
+			} `json:"data"`
+		}
+		if err := json.Unmarshal(message.Value, &event); err != nil {
+			log.Printf("Failed to unmarshal auth event: %v", err)
+			continue
+		}
+		if event.EventType == "client_revoked" {
+			c.denylist.RevokeClient(event.Data.ClientID, event.Data.ExpiresAt)
+		}
+	}
+}
+
+func (c *ClientRevocationsConsumer) pruneLoop(ctx context.Context) {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			c.denylist.Prune()
+		}
+	}
+}
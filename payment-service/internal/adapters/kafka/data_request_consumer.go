@@ -0,0 +1,108 @@
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+
+	"payment-service/internal/domain/models"
+	"payment-service/internal/ports"
+
+	"github.com/segmentio/kafka-go"
+)
+
+const dataRequestServiceName = "payment-service"
+
+type dataRequestResult struct {
+	RequestID string      `json:"request_id"`
+	Service   string      `json:"service"`
+	Type      string      `json:"type"`
+	Status    string      `json:"status"`
+	Error     string      `json:"error,omitempty"`
+	Data      interface{} `json:"data,omitempty"`
+}
+
+// DataRequestConsumer handles GDPR export and erasure requests from the
+// auth-events topic and reports the result on data-request-events.
+type DataRequestConsumer struct {
+	reader *kafka.Reader
+	writer *kafka.Writer
+}
+
+func NewDataRequestConsumer() *DataRequestConsumer {
+	return &DataRequestConsumer{
+		reader: kafka.NewReader(kafka.ReaderConfig{
+			Brokers: []string{"kafka:9092"},
+			Topic:   "auth-events",
+			GroupID: "payment-service-data-requests",
+		}),
+		writer: &kafka.Writer{
+			Addr:     kafka.TCP("kafka:9092"),
+			Topic:    "data-request-events",
+			Balancer: &kafka.LeastBytes{},
+		},
+	}
+}
+
+func (c *DataRequestConsumer) StartConsuming(ctx context.Context, paymentService ports.PaymentService) error {
+	log.Println("Starting to consume data requests from 'auth-events' topic")
+
+	for {
+		message, err := c.reader.ReadMessage(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				log.Println("Stopping data request consumer")
+				c.writer.Close()
+				return c.reader.Close()
+			}
+			log.Printf("Failed to read auth event: %v", err)
+			continue
+		}
+
+		var event struct {
+			EventType string `json:"event_type"`
+			UserID    string `json:"user_id"`
+			Data      struct {
+				RequestID string `json:"request_id"`
+			} `json:"data"`
+		}
+		if err := json.Unmarshal(message.Value, &event); err != nil {
+			log.Printf("Failed to unmarshal auth event: %v", err)
+			continue
+		}
+
+		result := dataRequestResult{RequestID: event.Data.RequestID, Service: dataRequestServiceName, Status: "completed"}
+		switch event.EventType {
+		case "user_export_requested":
+			result.Type = "export"
+			var payments []models.Payment
+			payments, err = paymentService.ExportUserData(ctx, event.UserID)
+			result.Data = map[string]interface{}{"payments": payments}
+		case "user_erasure_requested":
+			result.Type = "erasure"
+			err = paymentService.EraseUserData(ctx, event.UserID)
+		default:
+			continue
+		}
+		if err != nil {
+			log.Printf("Data request %s for user %s failed: %v", result.RequestID, event.UserID, err)
+			result.Status = "failed"
+			result.Error = err.Error()
+			result.Data = nil
+		}
+		c.publish(ctx, result)
+	}
+}
+
+func (c *DataRequestConsumer) publish(ctx context.Context, result dataRequestResult) {
+	value, err := json.Marshal(result)
+	if err != nil {
+		log.Printf("Failed to encode data request result: %v", err)
+		return
+	}
+	if err := c.writer.WriteMessages(ctx, kafka.Message{Key: []byte(result.RequestID), Value: value}); err != nil {
+		log.Printf("Failed to report data request %s: %v", result.RequestID, err)
+		return
+	}
+	log.Printf("Reported data request %s (%s: %s)", result.RequestID, result.Type, result.Status)
+}
@@ -9,15 +9,16 @@ import (
 )
 
 type PaymentEntity struct {
-	ID        uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
-	OrderID   uuid.UUID `gorm:"type:uuid;not null"`
-	UserID    uuid.UUID `gorm:"type:uuid;not null"`
-	Provider  string    `gorm:"size:32;not null"`
-	Amount    float64   `gorm:"not null"`
-	Currency  string    `gorm:"size:8;not null"`
-	Status    string    `gorm:"size:16;not null"`
-	CreatedAt time.Time `gorm:"autoCreateTime"`
-	UpdatedAt time.Time `gorm:"autoUpdateTime"`
+	ID         uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	OrderID    uuid.UUID `gorm:"type:uuid;not null"`
+	UserID     uuid.UUID `gorm:"type:uuid;not null"`
+	AuthUserID string    `gorm:"size:64;index"`
+	Provider   string    `gorm:"size:32;not null"`
+	Amount     float64   `gorm:"not null"`
+	Currency   string    `gorm:"size:8;not null"`
+	Status     string    `gorm:"size:16;not null"`
+	CreatedAt  time.Time `gorm:"autoCreateTime"`
+	UpdatedAt  time.Time `gorm:"autoUpdateTime"`
 }
 
 func (PaymentEntity) TableName() string {
@@ -26,28 +27,30 @@ func (PaymentEntity) TableName() string {
 
 func (e *PaymentEntity) ToDomain() *models.Payment {
 	return &models.Payment{
-		ID:        e.ID,
-		OrderID:   e.OrderID,
-		UserID:    e.UserID,
-		Provider:  e.Provider,
-		Amount:    e.Amount,
-		Currency:  e.Currency,
-		Status:    models.Status(e.Status),
-		CreatedAt: e.CreatedAt,
-		UpdatedAt: e.UpdatedAt,
+		ID:         e.ID,
+		OrderID:    e.OrderID,
+		UserID:     e.UserID,
+		AuthUserID: e.AuthUserID,
+		Provider:   e.Provider,
+		Amount:     e.Amount,
+		Currency:   e.Currency,
+		Status:     models.Status(e.Status),
+		CreatedAt:  e.CreatedAt,
+		UpdatedAt:  e.UpdatedAt,
 	}
 }
 
 func FromDomain(p *models.Payment) *PaymentEntity {
 	return &PaymentEntity{
-		ID:        p.ID,
-		OrderID:   p.OrderID,
-		UserID:    p.UserID,
-		Provider:  p.Provider,
-		Amount:    p.Amount,
-		Currency:  p.Currency,
-		Status:    string(p.Status),
-		CreatedAt: p.CreatedAt,
-		UpdatedAt: p.UpdatedAt,
+		ID:         p.ID,
+		OrderID:    p.OrderID,
+		UserID:     p.UserID,
+		AuthUserID: p.AuthUserID,
+		Provider:   p.Provider,
+		Amount:     p.Amount,
+		Currency:   p.Currency,
+		Status:     string(p.Status),
+		CreatedAt:  p.CreatedAt,
+		UpdatedAt:  p.UpdatedAt,
 	}
 }
@@ -81,6 +81,25 @@ func (r *PaymentRepository) FindByStatus(ctx context.Context, status models.Stat
 	return result, nil
 }
 
+func (r *PaymentRepository) FindByUser(ctx context.Context, authUserID string) ([]models.Payment, error) {
+	var entities []PaymentEntity
+	if err := r.db.WithContext(ctx).Where("auth_user_id = ?", authUserID).Order("created_at").Find(&entities).Error; err != nil {
+		return nil, err
+	}
+
+	result := make([]models.Payment, len(entities))
+	for i, entity := range entities {
+		result[i] = *entity.ToDomain()
+	}
+	return result, nil
+}
+
+func (r *PaymentRepository) AnonymizeUser(ctx context.Context, authUserID string) error {
+	return r.db.WithContext(ctx).Model(&PaymentEntity{}).
+		Where("auth_user_id = ?", authUserID).
+		Updates(map[string]interface{}{"user_id": uuid.Nil, "auth_user_id": ""}).Error
+}
+
 func (r *PaymentRepository) Update(ctx context.Context, payment *models.Payment) error {
 	entity := FromDomain(payment)
 	entity.UpdatedAt = time.Now()
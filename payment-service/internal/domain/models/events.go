@@ -5,6 +5,7 @@ import "time"
 type OrderCreatedEvent struct {
 	OrderID         string    `json:"order_id"`
 	UserID          string    `json:"user_id"`
+	AuthUserID      string    `json:"auth_user_id"`
 	TotalAmount     float64   `json:"total_amount"`
 	Currency        string    `json:"currency"`
 	PaymentProvider string    `json:"payment_provider"`
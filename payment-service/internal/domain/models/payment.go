@@ -16,15 +16,17 @@ const (
 )
 
 type Payment struct {
-	ID        uuid.UUID `json:"id"`
-	OrderID   uuid.UUID `json:"order_id" binding:"required"`
-	UserID    uuid.UUID `json:"user_id" binding:"required"`
-	Provider  string    `json:"provider" binding:"required,min=1,max=32"`
-	Amount    float64   `json:"amount" binding:"required,gt=0"`
-	Currency  string    `json:"currency" binding:"required,len=3"`
-	Status    Status    `json:"status"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID      uuid.UUID `json:"id"`
+	OrderID uuid.UUID `json:"order_id" binding:"required"`
+	UserID  uuid.UUID `json:"user_id" binding:"required"`
+	// AuthUserID is the auth-service user ID, set for payments of orders
+	AuthUserID string    `json:"auth_user_id,omitempty"`
+	Provider   string    `json:"provider" binding:"required,min=1,max=32"`
+	Amount     float64   `json:"amount" binding:"required,gt=0"`
+	Currency   string    `json:"currency" binding:"required,len=3"`
+	Status     Status    `json:"status"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
 }
 
 func NewPayment(orderID, userID uuid.UUID, provider string, amount float64, currency string) (*Payment, error) {
@@ -14,4 +14,9 @@ type PaymentRepository interface {
 	Update(ctx context.Context, p *models.Payment) error
 	Delete(ctx context.Context, id uuid.UUID) error
 	FindByStatus(ctx context.Context, status models.Status) ([]models.Payment, error)
+	// FindByUser returns the payments stored with the given auth-service user ID.
+	FindByUser(ctx context.Context, authUserID string) ([]models.Payment, error)
+	// AnonymizeUser removes the auth-service user ID from all payments of a
+	// user and replaces their user ID with the nil UUID.
+	AnonymizeUser(ctx context.Context, authUserID string) error
 }
@@ -15,4 +15,6 @@ type PaymentService interface {
 	DeletePayment(ctx context.Context, id uuid.UUID) error
 	GetPaymentsByStatus(ctx context.Context, status models.Status) ([]models.Payment, error)
 	ProcessOrderPayment(ctx context.Context, orderEvent models.OrderCreatedEvent) (*models.Payment, error)
+	ExportUserData(ctx context.Context, userID string) ([]models.Payment, error)
+	EraseUserData(ctx context.Context, userID string) error
 }
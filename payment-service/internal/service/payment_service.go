@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"payment-service/internal/domain/models"
@@ -29,6 +30,7 @@ func (s *PaymentService) CreatePayment(ctx context.Context, p models.Payment) (*
 	if err != nil {
 		return nil, err
 	}
+	payment.AuthUserID = p.AuthUserID
 
 	if err := s.repo.Save(ctx, payment); err != nil {
 		return nil, err
@@ -92,11 +94,12 @@ func (s *PaymentService) ProcessOrderPayment(ctx context.Context, orderEvent mod
 	}
 
 	payment := models.Payment{
-		OrderID:  orderID,
-		UserID:   userID,
-		Provider: orderEvent.PaymentProvider,
-		Amount:   orderEvent.TotalAmount,
-		Currency: orderEvent.Currency,
+		OrderID:    orderID,
+		UserID:     userID,
+		AuthUserID: orderEvent.AuthUserID,
+		Provider:   orderEvent.PaymentProvider,
+		Amount:     orderEvent.TotalAmount,
+		Currency:   orderEvent.Currency,
 	}
 
 	createdPayment, err := s.CreatePayment(ctx, payment)
@@ -166,3 +169,26 @@ func (s *PaymentService) ProcessOrderPayment(ctx context.Context, orderEvent mod
 	log.Printf("Payment %s successfully processed and marked as success", updatedPayment.ID.String())
 	return updatedPayment, nil
 }
+
+// ExportUserData returns all payments of a user for a GDPR data export.
+// Payments are matched by the auth-service user ID from the order; payments
+// created before it was stored cannot be attributed to a user.
+func (s *PaymentService) ExportUserData(ctx context.Context, userID string) ([]models.Payment, error) {
+	if userID == "" {
+		return nil, errors.New("user ID is required")
+	}
+	return s.repo.FindByUser(ctx, userID)
+}
+
+// EraseUserData anonymizes the payments of a user. The payments themselves
+// are kept because they are needed for accounting.
+func (s *PaymentService) EraseUserData(ctx context.Context, userID string) error {
+	if userID == "" {
+		return errors.New("user ID is required")
+	}
+	if err := s.repo.AnonymizeUser(ctx, userID); err != nil {
+		return err
+	}
+	log.Printf("Anonymized payments of user %s", userID)
+	return nil
+}
@@ -5,12 +5,15 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"strconv"
 	"time"
 
+	authadapter "shopping-service/internal/adapters/auth"
 	"shopping-service/internal/adapters/http"
 	"shopping-service/internal/adapters/http/middleware"
 	"shopping-service/internal/adapters/kafka"
 	mongoadapter "shopping-service/internal/adapters/mongo"
+	"shopping-service/internal/ports"
 	"shopping-service/internal/service"
 
 	"github.com/gin-gonic/gin"
@@ -43,7 +46,9 @@ func main() {
 
 	// 💡 Layer zusammensetzen
 	repo := mongoadapter.NewMongoRepository(db)
-	productService := service.NewProductService(repo)
+	categoryRepo := mongoadapter.NewCategoryRepo(db)
+	reservationRepo := mongoadapter.NewReservationRepo(db)
+	productService := service.NewProductService(repo, mongoadapter.NewProductSearch(db), categoryRepo, reservationRepo)
 
 	// 🌐 HTTP starten
 	r := gin.Default()
@@ -51,7 +56,7 @@ func main() {
 	// CORS Middleware hinzufügen
 	r.Use(func(c *gin.Context) {
 		c.Header("Access-Control-Allow-Origin", "*")
-		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
+		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
 		c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Authorization")
 		c.Header("Access-Control-Allow-Credentials", "true")
 
@@ -68,16 +73,85 @@ func main() {
 		c.Status(204)
 	})
 
+	kafkaBroker := os.Getenv("KAFKA_BROKER")
+	if kafkaBroker == "" {
+		kafkaBroker = "kafka:9092"
+	}
+
 	// Kafka Producer initialisieren
-	kafkaProducer := kafka.NewKafkaProducer("kafka:9092", "checkout")
+	kafkaProducer := kafka.NewKafkaProducer(kafkaBroker, "checkout")
+
+	// Widerrufene Tokens aus auth-events in die Denylist übernehmen; beim
+	// Start nur so weit zurück, wie Access-Tokens gültig sind (JWT_EXPIRY)
+	tokenLifetime := time.Hour
+	if expiry := os.Getenv("ACCESS_TOKEN_LIFETIME"); expiry != "" {
+		if seconds, err := strconv.Atoi(expiry); err == nil && seconds > 0 {
+			tokenLifetime = time.Duration(seconds) * time.Second
+		}
+	}
+	authEvents := kafka.NewAuthEventsConsumer(kafkaBroker, middleware.RevokedTokens, tokenLifetime)
+	go func() {
+		if err := authEvents.StartConsuming(context.Background()); err != nil {
+			log.Printf("Auth events consumer stopped: %v", err)
+		}
+	}()
+
+	// Änderungen am Katalog (product_updated, product_archived, product_deleted)
+	productEvents := kafka.NewKafkaProducer(kafkaBroker, kafka.ProductEventsTopic)
+	http.NewProductHandler(r, productService, kafkaProducer, productEvents)
+	http.NewCategoryHandler(r, service.NewCategoryService(categoryRepo, repo), productService)
+	// Profil (Lieferadresse) für den Checkout aus dem auth-service
+	var profiles ports.ProfileProvider
+	if authURL := os.Getenv("AUTH_SERVICE_URL"); authURL != "" {
+		profiles = authadapter.NewProfileClient(authURL)
+	}
+
+	cartRepo := mongoadapter.NewCartRepo(db)
+
+	// Reservierungen halten den Bestand bis zum Ergebnis der Zahlung
+	reservationTTL := 15 * time.Minute
+	if ttl := os.Getenv("RESERVATION_TTL"); ttl != "" {
+		if seconds, err := strconv.Atoi(ttl); err == nil && seconds > 0 {
+			reservationTTL = time.Duration(seconds) * time.Second
+		}
+	}
+	inventory := service.NewInventoryService(repo, reservationRepo, reservationTTL)
+	paymentEvents := kafka.NewPaymentEventsConsumer(kafkaBroker, inventory)
+	go func() {
+		if err := paymentEvents.StartConsuming(context.Background()); err != nil {
+			log.Printf("Payment events consumer stopped: %v", err)
+		}
+	}()
+	// Abgelaufene Reservierungen freigeben, falls keine Zahlung ankommt
+	go func() {
+		ticker := time.NewTicker(time.Minute)
+		defer ticker.Stop()
+		for range ticker.C {
+			released, err := inventory.ReleaseExpired()
+			if err != nil {
+				log.Printf("Releasing expired reservations failed: %v", err)
+			}
+			if released > 0 {
+				log.Printf("Released %d expired reservations", released)
+			}
+		}
+	}()
+
+	// Datenexport und Löschung nach DSGVO, beauftragt über auth-events
+	dataRequests := kafka.NewDataRequestConsumer(kafkaBroker,
+		kafka.NewKafkaProducer(kafkaBroker, kafka.DataRequestEventsTopic),
+		service.NewPrivacyService(repo, cartRepo, reservationRepo))
+	go func() {
+		if err := dataRequests.StartConsuming(context.Background()); err != nil {
+			log.Printf("Data request consumer stopped: %v", err)
+		}
+	}()
 
-	http.NewProductHandler(r, productService, kafkaProducer)
 	userGroup := r.Group("/")
 	userGroup.Use(middleware.JWTMiddleware())
 	{
-		cartRepo := mongoadapter.NewCartRepo(db)
 		cartSvc := service.NewCartService(cartRepo)
-		http.NewCartHandler(userGroup, cartSvc, productService, kafkaProducer)
+		http.NewCartHandler(userGroup, cartSvc, productService, inventory, kafkaProducer, profiles)
 	}
 	
 	// Health Check Endpoint
@@ -0,0 +1,50 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"shopping-service/internal/domain"
+	"shopping-service/internal/ports"
+)
+
+// ProfileClient reads the user's profile from auth-service's GET /me,
+// forwarding the user's own token so no extra credentials are needed.
+type ProfileClient struct {
+	baseURL string
+	client  *http.Client
+}
+
+func NewProfileClient(baseURL string) ports.ProfileProvider {
+	return &ProfileClient{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		client:  &http.Client{Timeout: 5 * time.Second},
+	}
+}
+
+func (p *ProfileClient) GetProfile(ctx context.Context, bearerToken string) (*domain.Profile, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.baseURL+"/me", nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+bearerToken)
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("profile request failed with status %d", resp.StatusCode)
+	}
+
+	var profile domain.Profile
+	if err := json.NewDecoder(resp.Body).Decode(&profile); err != nil {
+		return nil, err
+	}
+	return &profile, nil
+}
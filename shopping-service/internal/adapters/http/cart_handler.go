@@ -1,14 +1,19 @@
 package http
 
 import (
+	"errors"
 	"fmt"
+	"log"
 	"net/http"
 	"shopping-service/internal/adapters/http/middleware"
 	"shopping-service/internal/adapters/kafka"
+	"shopping-service/internal/domain"
+	"shopping-service/internal/ports"
 	"shopping-service/internal/service"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 )
 
 type AddToCartReq struct {
@@ -16,19 +21,29 @@ type AddToCartReq struct {
 	Qty       int    `json:"qty" binding:"required"`
 }
 
+type CheckoutReq struct {
+	// AddressID selects a shipping address from the profile; empty uses the default address
+	AddressID string `json:"address_id"`
+}
+
 type CartHandler struct {
 	cartSvc       *service.CartService
 	productSvc    *service.ProductService
+	inventory     *service.InventoryService
 	kafkaProducer *kafka.KafkaProducer
+	profiles      ports.ProfileProvider
 }
 
-func NewCartHandler(rg *gin.RouterGroup, cs *service.CartService, ps *service.ProductService, kp *kafka.KafkaProducer) {
-	h := &CartHandler{cartSvc: cs, productSvc: ps, kafkaProducer: kp}
+// NewCartHandler registers the cart routes. profiles may be nil, then orders
+// are created without shipping address.
+func NewCartHandler(rg *gin.RouterGroup, cs *service.CartService, ps *service.ProductService, inv *service.InventoryService, kp *kafka.KafkaProducer, profiles ports.ProfileProvider) {
+	h := &CartHandler{cartSvc: cs, productSvc: ps, inventory: inv, kafkaProducer: kp, profiles: profiles}
 	rg.POST("/cart", h.AddToCart)
 	rg.GET("/cart", h.GetCart)
 	rg.PUT("/cart", h.UpdateCartItem)                // Update quantity
 	rg.DELETE("/cart/:product_id", h.RemoveFromCart) // Remove item
-	rg.POST("/checkout", h.Checkout)                 // protected: creates order event
+	// protected: creates order event, needs a verified email
+	rg.POST("/checkout", middleware.RequireVerified(), h.Checkout)
 }
 
 func (h *CartHandler) AddToCart(c *gin.Context) {
@@ -42,6 +57,28 @@ func (h *CartHandler) AddToCart(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	if req.Qty <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "quantity must be greater than 0"})
+		return
+	}
+
+	// Menge im Warenkorb mitzählen, sonst ließe sich der Bestand stückweise überschreiten
+	cart, err := h.cartSvc.GetCart(uid)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed"})
+		return
+	}
+	qty := req.Qty
+	for _, item := range cart.Items {
+		if item.ProductID == req.ProductID {
+			qty += item.Qty
+		}
+	}
+	if err := h.inventory.CheckAvailable(req.ProductID, qty); err != nil {
+		respondStockError(c, err)
+		return
+	}
+
 	if err := h.cartSvc.AddToCart(uid, req.ProductID, req.Qty); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed"})
 		return
@@ -75,6 +112,42 @@ func (h *CartHandler) GetCart(c *gin.Context) {
 
 func (h *CartHandler) Checkout(c *gin.Context) {
 	uid, _ := middleware.GetUserID(c)
+
+	var req CheckoutReq
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	// Lieferadresse und Name aus dem Profil im auth-service. Ohne gewählte
+	// Adresse ist das Profil optional, die Order geht dann ohne beides raus.
+	var profile *domain.Profile
+	var shippingAddress *domain.Address
+	if h.profiles != nil {
+		token, _ := middleware.GetBearerToken(c)
+		p, err := h.profiles.GetProfile(c.Request.Context(), token)
+		switch {
+		case err == nil:
+			profile = p
+			if address, ok := profile.Address(req.AddressID); ok {
+				shippingAddress = address
+			} else if req.AddressID != "" {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "unknown address_id"})
+				return
+			}
+		case req.AddressID != "":
+			c.JSON(http.StatusBadGateway, gin.H{"error": "failed to load profile"})
+			return
+		default:
+			log.Printf("Failed to load profile of %s, checking out without shipping address: %v", uid, err)
+		}
+	} else if req.AddressID != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "addresses are not available"})
+		return
+	}
+
 	cart, err := h.cartSvc.GetCart(uid)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get cart"})
@@ -102,6 +175,12 @@ func (h *CartHandler) Checkout(c *gin.Context) {
 			})
 			return
 		}
+		if product.Archived {
+			c.JSON(http.StatusConflict, gin.H{
+				"error": fmt.Sprintf("product %s is no longer available", item.ProductID),
+			})
+			return
+		}
 		
 		itemPrice := product.Price * float64(item.Qty)
 		amount += itemPrice
@@ -115,10 +194,20 @@ func (h *CartHandler) Checkout(c *gin.Context) {
 		})
 	}
 
+	// Bestand reservieren, bis die Zahlung erfolgreich war oder fehlschlägt.
+	// Die Order-ID ist eine UUID, damit sie über checkout- und payment-service
+	// hinweg gleich bleibt
+	orderID := uuid.New().String()
+	reservation, err := h.inventory.Reserve(orderID, uid, cart.Items)
+	if err != nil {
+		respondStockError(c, err)
+		return
+	}
+
 	// Erstelle umfassende Bestellnachricht
 	order := map[string]interface{}{
 		"event_type":   "order_created",
-		"order_id":     fmt.Sprintf("ord-%d", time.Now().UnixNano()),
+		"order_id":     orderID,
 		"user_id":      uid,
 		"items":        orderItems,
 		"product_ids":  productIds,
@@ -127,8 +216,15 @@ func (h *CartHandler) Checkout(c *gin.Context) {
 		"status":       "pending",
 		"timestamp":    time.Now().Format(time.RFC3339),
 	}
+	if shippingAddress != nil {
+		order["shipping_address"] = shippingAddress
+	}
+	if profile != nil && profile.DisplayName != "" {
+		order["customer_name"] = profile.DisplayName
+	}
 
 	if err := h.kafkaProducer.SendMessage(order); err != nil {
+		_ = h.inventory.Release(orderID)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to produce order"})
 		return
 	}
@@ -137,10 +233,12 @@ func (h *CartHandler) Checkout(c *gin.Context) {
 	_ = h.cartSvc.ClearCart(uid)
 
 	c.JSON(http.StatusOK, gin.H{
-		"status":       "order_created",
-		"order_id":     order["order_id"],
-		"total_amount": amount,
-		"items_count":  len(cart.Items),
+		"status":           "order_created",
+		"order_id":         order["order_id"],
+		"total_amount":     amount,
+		"items_count":      len(cart.Items),
+		"shipping_address": shippingAddress,
+		"reserved_until":   reservation.ExpiresAt,
 	})
 }
 
@@ -162,6 +260,11 @@ func (h *CartHandler) UpdateCartItem(c *gin.Context) {
 		return
 	}
 
+	if err := h.inventory.CheckAvailable(req.ProductID, req.Qty); err != nil {
+		respondStockError(c, err)
+		return
+	}
+
 	if err := h.cartSvc.UpdateCartItem(uid, req.ProductID, req.Qty); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update cart item"})
 		return
@@ -211,3 +314,24 @@ func (h *CartHandler) RemoveFromCart(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{"message": "item removed from cart successfully"})
 }
+
+// respondStockError answers with 409 and the available quantity if a product
+// is out of stock.
+func respondStockError(c *gin.Context, err error) {
+	var outOfStock *service.OutOfStockError
+	switch {
+	case errors.As(err, &outOfStock):
+		c.JSON(http.StatusConflict, gin.H{
+			"error":      "out of stock",
+			"product_id": outOfStock.ProductID,
+			"requested":  outOfStock.Requested,
+			"available":  outOfStock.Available,
+		})
+	case errors.Is(err, service.ErrProductNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "product not found"})
+	case errors.Is(err, service.ErrInvalidQuantity):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to check stock"})
+	}
+}
@@ -0,0 +1,122 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"shopping-service/internal/adapters/http/middleware"
+	"shopping-service/internal/domain"
+	"shopping-service/internal/ports"
+	"shopping-service/internal/service"
+)
+
+type CategoryHandler struct {
+	categories *service.CategoryService
+	products   ports.ProductService
+}
+
+func NewCategoryHandler(r *gin.Engine, categories *service.CategoryService, products ports.ProductService) {
+	handler := &CategoryHandler{categories: categories, products: products}
+
+	// Öffentliche Navigation
+	r.GET("/categories", handler.Tree)
+	r.GET("/categories/:slug/products", handler.ListProducts)
+
+	// Kategorien pflegen (nur mit categories:manage, z.B. admin)
+	adminGroup := r.Group("/categories")
+	adminGroup.Use(middleware.JWTMiddleware())
+	adminGroup.Use(middleware.RequirePermission(middleware.PermCategoriesManage))
+	adminGroup.POST("", handler.Create)
+	adminGroup.PATCH("/:slug", handler.Update)
+	adminGroup.DELETE("/:slug", handler.Delete)
+}
+
+func (h *CategoryHandler) Tree(c *gin.Context) {
+	tree, err := h.categories.Tree()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching categories"})
+		return
+	}
+	c.JSON(http.StatusOK, tree)
+}
+
+// ListProducts lists the products of a category including all subcategories,
+// unless include_subcategories=false. It takes the same parameters as
+// GET /products.
+func (h *CategoryHandler) ListProducts(c *gin.Context) {
+	category, ids, err := h.categories.SubtreeIDs(c.Param("slug"), c.Query("include_subcategories") != "false")
+	if err != nil {
+		respondCategoryError(c, err)
+		return
+	}
+	filter, ok := productFilterFromQuery(c)
+	if !ok {
+		return
+	}
+	filter.CategoryIDs = ids
+	page, err := h.products.ListProducts(filter)
+	if err != nil {
+		respondListError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, struct {
+		Category *domain.Category `json:"category"`
+		*domain.ProductPage
+	}{category, page})
+}
+
+func (h *CategoryHandler) Create(c *gin.Context) {
+	var req struct {
+		Name       string `json:"name" binding:"required"`
+		Slug       string `json:"slug"`
+		ParentSlug string `json:"parent_slug"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "name is required"})
+		return
+	}
+	category, err := h.categories.Create(req.Name, req.Slug, req.ParentSlug)
+	if err != nil {
+		respondCategoryError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, category)
+}
+
+// Update renames a category (name, slug) or moves it with parent_slug; an
+// empty parent_slug moves it to the top level.
+func (h *CategoryHandler) Update(c *gin.Context) {
+	var update domain.CategoryUpdate
+	if err := c.ShouldBindJSON(&update); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+	category, err := h.categories.Update(c.Param("slug"), update)
+	if err != nil {
+		respondCategoryError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, category)
+}
+
+func (h *CategoryHandler) Delete(c *gin.Context) {
+	if err := h.categories.Delete(c.Param("slug")); err != nil {
+		respondCategoryError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func respondCategoryError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrCategoryNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrInvalidCategory), errors.Is(err, service.ErrCategoryCycle):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrCategoryExists), errors.Is(err, service.ErrCategoryHasChildren):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error saving category"})
+	}
+}
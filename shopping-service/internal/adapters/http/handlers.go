@@ -5,6 +5,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"shopping-service/internal/adapters/kafka"
+	"shopping-service/internal/domain"
 	"shopping-service/internal/service"
 )
 
@@ -19,7 +20,7 @@ type Order struct {
 // ListProductsHandler
 func ListProductsHandler(service *service.ProductService) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		products, err := service.GetAllProducts()
+		products, err := service.ListProducts(domain.ProductFilter{})
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching products"})
 			return
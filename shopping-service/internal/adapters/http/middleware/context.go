@@ -1,6 +1,10 @@
 package middleware
 
-import "github.com/gin-gonic/gin"
+import (
+	"slices"
+
+	"github.com/gin-gonic/gin"
+)
 
 func GetUserID(c *gin.Context) (string, bool) {
 	id, ok := c.Get(ContextUserIDKey)
@@ -10,3 +14,19 @@ func GetUserID(c *gin.Context) (string, bool) {
 	s, ok := id.(string)
 	return s, ok
 }
+
+// GetBearerToken returns the raw token, e.g. to call other services on behalf of the user.
+func GetBearerToken(c *gin.Context) (string, bool) {
+	return extractTokenFromHeader(c)
+}
+
+// HasPermission reports whether the token carries a permission, e.g. to allow
+// admins to change other users' records.
+func HasPermission(c *gin.Context, permission string) bool {
+	v, ok := c.Get(ContextPermissionsKey)
+	if !ok {
+		return false
+	}
+	permissions, _ := v.([]string)
+	return slices.Contains(permissions, permission)
+}
@@ -0,0 +1,84 @@
+package middleware
+
+import (
+	"sync"
+	"time"
+)
+
+// Denylist holds revoked tokens announced by auth-service on the auth-events
+// topic. Entries are dropped once the tokens they block have expired anyway.
+type Denylist struct {
+	mu     sync.RWMutex
+	tokens map[string]time.Time // jti -> expires_at
+	users  map[string]userRevocation
+}
+
+type userRevocation struct {
+	revokedBefore time.Time
+	expiresAt     time.Time
+}
+
+func NewDenylist() *Denylist {
+	return &Denylist{
+		tokens: map[string]time.Time{},
+		users:  map[string]userRevocation{},
+	}
+}
+
+// RevokedTokens is shared by all JWTMiddleware instances and filled by the
+// auth-events consumer.
+var RevokedTokens = NewDenylist()
+
+// RevokeToken blocks a single token by its jti.
+func (d *Denylist) RevokeToken(jti string, expiresAt time.Time) {
+	if jti == "" || time.Now().After(expiresAt) {
+		return
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.tokens[jti] = expiresAt
+}
+
+// RevokeUser blocks all tokens of a user issued before revokedBefore.
+func (d *Denylist) RevokeUser(userID string, revokedBefore, expiresAt time.Time) {
+	if userID == "" || time.Now().After(expiresAt) {
+		return
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if current, ok := d.users[userID]; ok && current.revokedBefore.After(revokedBefore) {
+		return
+	}
+	d.users[userID] = userRevocation{revokedBefore: revokedBefore, expiresAt: expiresAt}
+}
+
+// IsRevoked reports whether the token was revoked individually or by a
+// user-wide revocation.
+func (d *Denylist) IsRevoked(jti, userID string, issuedAt time.Time) bool {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	if _, ok := d.tokens[jti]; ok && jti != "" {
+		return true
+	}
+	if r, ok := d.users[userID]; ok && issuedAt.Before(r.revokedBefore) {
+		return true
+	}
+	return false
+}
+
+// Prune removes entries whose tokens have expired.
+func (d *Denylist) Prune() {
+	now := time.Now()
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for jti, exp := range d.tokens {
+		if now.After(exp) {
+			delete(d.tokens, jti)
+		}
+	}
+	for userID, r := range d.users {
+		if now.After(r.expiresAt) {
+			delete(d.users, userID)
+		}
+	}
+}
@@ -2,10 +2,12 @@ package middleware
 
 import (
 	"log"
+	"math"
 	"net/http"
 	"os"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -15,6 +17,14 @@ import (
 // Context key names
 const ContextUserIDKey = "user_id"
 const ContextUserRoleKey = "user_role"
+const ContextPermissionsKey = "permissions"
+const ContextTokenTypeKey = "token_type"
+
+// Token types issued by auth-service ("typ" claim)
+const (
+	TokenTypeAccess     = "access"
+	TokenTypeUnverified = "unverified"
+)
 
 // Extract token from Authorization header: "Bearer <token>"
 func extractTokenFromHeader(c *gin.Context) (string, bool) {
@@ -29,13 +39,44 @@ func extractTokenFromHeader(c *gin.Context) (string, bool) {
 	return parts[1], true
 }
 
+var (
+	keyfuncOnce sync.Once
+	keyfunc     jwt.Keyfunc
+	validAlgs   []string
+)
+
+// tokenKeyfunc is built once and shared by all JWTMiddleware instances, so the
+// JWKS cache is not duplicated per route group. With JWKS_URL set, tokens are
+// verified against auth-service's public keys; JWT_SECRET (HS256) remains as a
+// fallback for setups without asymmetric signing.
+func tokenKeyfunc() (jwt.Keyfunc, []string) {
+	keyfuncOnce.Do(func() {
+		if url := os.Getenv("JWKS_URL"); url != "" {
+			ttl := 5 * time.Minute
+			if v, err := strconv.Atoi(os.Getenv("JWKS_CACHE_TTL")); err == nil && v > 0 {
+				ttl = time.Duration(v) * time.Second
+			}
+			log.Printf("Verifying JWTs via JWKS from %s", url)
+			keyfunc = NewJWKSCache(url, ttl).Keyfunc
+			validAlgs = []string{jwt.SigningMethodRS256.Alg(), jwt.SigningMethodEdDSA.Alg()}
+			return
+		}
+
+		secret := os.Getenv("JWT_SECRET")
+		if secret == "" {
+			panic("JWKS_URL or JWT_SECRET must be set")
+		}
+		keyfunc = func(t *jwt.Token) (interface{}, error) {
+			return []byte(secret), nil
+		}
+		validAlgs = []string{jwt.SigningMethodHS256.Alg()}
+	})
+	return keyfunc, validAlgs
+}
+
 // JWTMiddleware returns a Gin middleware that validates JWT and sets user_id and user_role in context
 func JWTMiddleware() gin.HandlerFunc {
-	secret := os.Getenv("JWT_SECRET")
-	if secret == "" {
-		panic("JWT_SECRET not set")
-	}
-	log.Printf("DEBUG: JWT_SECRET = '%s'", secret)
+	keyfunc, algs := tokenKeyfunc()
 	return func(c *gin.Context) {
 		tokenStr, ok := extractTokenFromHeader(c)
 		if !ok {
@@ -43,12 +84,7 @@ func JWTMiddleware() gin.HandlerFunc {
 			return
 		}
 		log.Printf("DEBUG: Received token = '%s'", tokenStr[:50]+"...")
-		token, err := jwt.Parse(tokenStr, func(t *jwt.Token) (interface{}, error) {
-			if t.Method.Alg() != jwt.SigningMethodHS256.Alg() {
-				return nil, jwt.ErrTokenUnverifiable
-			}
-			return []byte(secret), nil
-		}, jwt.WithLeeway(5*time.Second))
+		token, err := jwt.Parse(tokenStr, keyfunc, jwt.WithValidMethods(algs), jwt.WithLeeway(5*time.Second))
 
 		if err != nil || !token.Valid {
 			log.Printf("DEBUG: Token validation failed: %v", err)
@@ -73,6 +109,17 @@ func JWTMiddleware() gin.HandlerFunc {
 			}
 		}
 
+		// tokens without typ predate the claim and are treated as access tokens
+		tokenType, _ := claims["typ"].(string)
+		if tokenType == "" {
+			tokenType = TokenTypeAccess
+		}
+		if tokenType != TokenTypeAccess && tokenType != TokenTypeUnverified {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "token type not allowed"})
+			return
+		}
+		c.Set(ContextTokenTypeKey, tokenType)
+
 		// extract user_id (can be string or number)
 		var userID string
 		if id, ok := claims["user_id"]; ok {
@@ -90,12 +137,22 @@ func JWTMiddleware() gin.HandlerFunc {
 			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "user_id claim missing"})
 			return
 		}
+
+		// revoked via logout or by an admin (auth-events topic)
+		jti, _ := claims["jti"].(string)
+		issuedAt := issuedAtClaim(claims["iat"])
+		if RevokedTokens.IsRevoked(jti, userID, issuedAt) {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "token revoked"})
+			return
+		}
 		c.Set(ContextUserIDKey, userID)
 
 		// extract user_role
-		if role, ok := claims["role"].(string); ok && role != "" {
+		role, _ := claims["role"].(string)
+		if role != "" {
 			c.Set(ContextUserRoleKey, role)
 		}
+		c.Set(ContextPermissionsKey, tokenPermissions(claims))
 
 		c.Next()
 	}
@@ -113,6 +170,90 @@ func RequireRole(role string) gin.HandlerFunc {
 	}
 }
 
+// Permissions defined by auth-service (domain/permissions.go)
+const (
+	PermProductsWrite    = "products:write"
+	PermProductsWriteAny = "products:write:any"
+	PermCategoriesManage = "categories:manage"
+	PermOrdersReadAny    = "orders:read:any"
+	PermPaymentsRefund   = "payments:refund"
+)
+
+// RequirePermission passes only if the token carries all given permissions.
+func RequirePermission(permissions ...string) gin.HandlerFunc {
+	return requirePermissions(permissions, true)
+}
+
+// RequireAnyPermission passes if the token carries at least one of them.
+func RequireAnyPermission(permissions ...string) gin.HandlerFunc {
+	return requirePermissions(permissions, false)
+}
+
+func requirePermissions(required []string, all bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		granted := map[string]bool{}
+		if v, ok := c.Get(ContextPermissionsKey); ok {
+			for _, p := range v.([]string) {
+				granted[p] = true
+			}
+		}
+		allowed := all
+		for _, p := range required {
+			if granted[p] != all {
+				allowed = !all
+				break
+			}
+		}
+		if !allowed {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "insufficient permissions"})
+			return
+		}
+		c.Next()
+	}
+}
+
+// tokenPermissions reads the "permissions" claim. The claim is trusted as is:
+// auth-service revokes all tokens of a user whose role changes, and the
+// user_tokens_revoked event puts them on RevokedTokens, so a downgrade takes
+// effect here without waiting for expiry. Tokens without the claim, including
+// restricted tokens of unverified accounts, carry no permissions.
+func tokenPermissions(claims jwt.MapClaims) []string {
+	raw, ok := claims["permissions"].([]interface{})
+	if !ok {
+		return nil
+	}
+	permissions := make([]string, 0, len(raw))
+	for _, p := range raw {
+		if s, ok := p.(string); ok {
+			permissions = append(permissions, s)
+		}
+	}
+	return permissions
+}
+
+// RequireVerified rejects restricted tokens issued to accounts whose email is
+// not yet verified.
+func RequireVerified() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if v, _ := c.Get(ContextTokenTypeKey); v == TokenTypeUnverified {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "email not verified"})
+			return
+		}
+		c.Next()
+	}
+}
+
+// issuedAtClaim reads iat with millisecond precision. auth-service issues
+// fractional iat values so that a user-wide revocation also covers tokens
+// from the same second; jwt.NumericDate truncates them to whole seconds.
+func issuedAtClaim(v interface{}) time.Time {
+	seconds, ok := v.(float64)
+	if !ok {
+		return time.Time{}
+	}
+	return time.UnixMilli(int64(math.Round(seconds * 1000)))
+}
+
 // helper to convert interface to string (simple)
 func toString(v interface{}) string {
 	switch x := v.(type) {
@@ -1,34 +1,47 @@
 package http
 
 import (
+	"errors"
+	"log"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"shopping-service/internal/adapters/http/middleware"
 	"shopping-service/internal/adapters/kafka"
 	"shopping-service/internal/domain"
 	"shopping-service/internal/ports"
+	"shopping-service/internal/service"
 )
 
 type ProductHandler struct {
 	service       ports.ProductService
 	kafkaProducer *kafka.KafkaProducer
+	events        *kafka.KafkaProducer
 }
 
-func NewProductHandler(r *gin.Engine, service ports.ProductService, producer *kafka.KafkaProducer) {
+func NewProductHandler(r *gin.Engine, service ports.ProductService, producer, events *kafka.KafkaProducer) {
 	handler := &ProductHandler{
 		service:       service,
 		kafkaProducer: producer,
+		events:        events,
 	}
 
 	// Öffentliche Route
 	r.GET("/products", handler.ListProducts)
+	r.GET("/products/search", handler.SearchProducts)
 
-	// Admin-Gruppe: Produkte anlegen (nur mit JWT und admin-Rolle)
+	// Admin-Gruppe: Produkte anlegen (nur mit JWT und products:write, z.B. admin oder seller)
 	adminGroup := r.Group("/")
 	adminGroup.Use(middleware.JWTMiddleware())
-	adminGroup.Use(middleware.RequireRole("admin"))
+	adminGroup.Use(middleware.RequirePermission(middleware.PermProductsWrite))
 	adminGroup.POST("/products", handler.CreateProduct)
+	// Ändern und Archivieren: eigene Produkte, fremde nur mit products:write:any
+	adminGroup.PUT("/products/:id", handler.ReplaceProduct)
+	adminGroup.PATCH("/products/:id", handler.UpdateProduct)
+	adminGroup.DELETE("/products/:id", handler.DeleteProduct)
 
 	// User-Gruppe: Cart & Checkout (nur mit JWT, Rolle egal)
 	userGroup := r.Group("/")
@@ -50,6 +63,10 @@ func (h *ProductHandler) CreateProduct(c *gin.Context) {
 
 	err := h.service.CreateProduct(&product)
 	if err != nil {
+		if errors.Is(err, service.ErrUnknownCategory) || errors.Is(err, service.ErrInvalidProduct) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error saving product"})
 		return
 	}
@@ -63,11 +80,189 @@ func (h *ProductHandler) CreateProduct(c *gin.Context) {
 	c.JSON(http.StatusCreated, product)
 }
 
+// ListProducts supports the query parameters name, min_price, max_price,
+// sort (created, name, price, "-" for descending), limit and cursor.
 func (h *ProductHandler) ListProducts(c *gin.Context) {
-	products, err := h.service.GetAllProducts()
+	filter, ok := productFilterFromQuery(c)
+	if !ok {
+		return
+	}
+	page, err := h.service.ListProducts(filter)
+	if err != nil {
+		respondListError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, page)
+}
+
+// productFilterFromQuery reads the list parameters and answers 400 itself if
+// one of them is invalid.
+func productFilterFromQuery(c *gin.Context) (domain.ProductFilter, bool) {
+	filter := domain.ProductFilter{
+		Name:   c.Query("name"),
+		Sort:   c.Query("sort"),
+		Cursor: c.Query("cursor"),
+	}
+	var err error
+	if filter.MinPrice, err = queryFloat(c, "min_price"); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid min_price"})
+		return filter, false
+	}
+	if filter.MaxPrice, err = queryFloat(c, "max_price"); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid max_price"})
+		return filter, false
+	}
+	if v := c.Query("limit"); v != "" {
+		if filter.Limit, err = strconv.Atoi(v); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid limit"})
+			return filter, false
+		}
+	}
+	return filter, true
+}
+
+func respondListError(c *gin.Context, err error) {
+	if errors.Is(err, service.ErrInvalidProductFilter) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching products"})
+}
+
+// SearchProducts expects the search text in q and an optional limit.
+func (h *ProductHandler) SearchProducts(c *gin.Context) {
+	limit := 0
+	if v := c.Query("limit"); v != "" {
+		var err error
+		if limit, err = strconv.Atoi(v); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid limit"})
+			return
+		}
+	}
+	hits, err := h.service.SearchProducts(c.Query("q"), limit)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidSearchQuery) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error searching products"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"query": c.Query("q"), "results": hits})
+}
+
+func queryFloat(c *gin.Context, key string) (*float64, error) {
+	v := c.Query(key)
+	if v == "" {
+		return nil, nil
+	}
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return nil, err
+	}
+	return &f, nil
+}
+
+// ReplaceProduct expects the complete product (name and price, a missing
+// description or category list is cleared).
+func (h *ProductHandler) ReplaceProduct(c *gin.Context) {
+	var req struct {
+		Name        *string              `json:"name" binding:"required"`
+		Description string               `json:"description"`
+		Price       *float64             `json:"price" binding:"required"`
+		CategoryIDs []primitive.ObjectID `json:"category_ids"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "name and price are required"})
+		return
+	}
+	h.update(c, domain.ProductUpdate{Name: req.Name, Description: &req.Description, Price: req.Price, CategoryIDs: &req.CategoryIDs})
+}
+
+// UpdateProduct changes only the fields present in the body.
+func (h *ProductHandler) UpdateProduct(c *gin.Context) {
+	var update domain.ProductUpdate
+	if err := c.ShouldBindJSON(&update); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+	if update.Name == nil && update.Description == nil && update.Price == nil && update.CategoryIDs == nil && update.Stock == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "nothing to update"})
+		return
+	}
+	h.update(c, update)
+}
+
+func (h *ProductHandler) update(c *gin.Context, update domain.ProductUpdate) {
+	userID, _ := middleware.GetUserID(c)
+	product, err := h.service.UpdateProduct(c.Param("id"), update, userID, middleware.HasPermission(c, middleware.PermProductsWriteAny))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching products"})
+		respondProductError(c, err)
 		return
 	}
-	c.JSON(http.StatusOK, products)
+	h.sendEvent("product_updated", product, userID, nil)
+	c.JSON(http.StatusOK, product)
+}
+
+// DeleteProduct archives the product. With ?permanent=true it is removed from
+// the database instead, which needs products:write:any.
+func (h *ProductHandler) DeleteProduct(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+	anyOwner := middleware.HasPermission(c, middleware.PermProductsWriteAny)
+
+	if c.Query("permanent") == "true" {
+		if !anyOwner {
+			c.JSON(http.StatusForbidden, gin.H{"error": "insufficient permissions"})
+			return
+		}
+		product, err := h.service.DeleteProduct(c.Param("id"), userID, anyOwner)
+		if err != nil {
+			respondProductError(c, err)
+			return
+		}
+		h.sendEvent("product_deleted", product, userID, nil)
+		c.Status(http.StatusNoContent)
+		return
+	}
+
+	product, err := h.service.ArchiveProduct(c.Param("id"), userID, anyOwner)
+	if err != nil {
+		respondProductError(c, err)
+		return
+	}
+	h.sendEvent("product_archived", product, userID, nil)
+	c.JSON(http.StatusOK, product)
+}
+
+// sendEvent publishes a change on product-events. The change is already
+// stored, so a Kafka error is only logged.
+func (h *ProductHandler) sendEvent(eventType string, product *domain.Product, actorID string, extra gin.H) {
+	event := map[string]interface{}{
+		"event_type": eventType,
+		"product_id": product.ID.Hex(),
+		"actor_id":   actorID,
+		"product":    product,
+		"timestamp":  time.Now().Format(time.RFC3339),
+	}
+	for k, v := range extra {
+		event[k] = v
+	}
+	if err := h.events.SendMessage(event); err != nil {
+		log.Printf("Failed to send %s event for product %s: %v", eventType, product.ID.Hex(), err)
+	}
+}
+
+func respondProductError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrProductNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrNotProductOwner):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrProductReserved):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrInvalidProduct), errors.Is(err, service.ErrUnknownCategory):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error saving product"})
+	}
 }
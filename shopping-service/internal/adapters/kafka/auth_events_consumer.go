@@ -0,0 +1,145 @@
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"sync"
+	"time"
+
+	"shopping-service/internal/adapters/http/middleware"
+
+	"github.com/segmentio/kafka-go"
+)
+
+const authEventsTopic = "auth-events"
+
+type authEvent struct {
+	EventType string `json:"event_type"`
+	UserID    string `json:"user_id"`
+	Data      struct {
+		JTI           string    `json:"jti"`
+		ExpiresAt     time.Time `json:"expires_at"`
+		RevokedBefore time.Time `json:"revoked_before"`
+	} `json:"data"`
+}
+
+// replayMargin covers clock skew and the JWT leeway when choosing where to
+// start reading.
+const replayMargin = time.Minute
+
+// AuthEventsConsumer fills the token denylist from auth-service revocations.
+// Every instance needs every event, so instead of a consumer group each
+// partition is read directly. Only events younger than the access token
+// lifetime are replayed on start; older revocations refer to tokens that
+// have already expired.
+type AuthEventsConsumer struct {
+	broker        string
+	denylist      *middleware.Denylist
+	tokenLifetime time.Duration
+}
+
+func NewAuthEventsConsumer(broker string, denylist *middleware.Denylist, tokenLifetime time.Duration) *AuthEventsConsumer {
+	return &AuthEventsConsumer{broker: broker, denylist: denylist, tokenLifetime: tokenLifetime}
+}
+
+func (c *AuthEventsConsumer) StartConsuming(ctx context.Context) error {
+	partitions, err := c.partitions(ctx)
+	if err != nil {
+		return err
+	}
+	log.Printf("Starting to consume from '%s' topic (%d partitions)", authEventsTopic, len(partitions))
+
+	go c.pruneLoop(ctx)
+
+	var wg sync.WaitGroup
+	for _, p := range partitions {
+		wg.Add(1)
+		go func(partition int) {
+			defer wg.Done()
+			c.consumePartition(ctx, partition)
+		}(p.ID)
+	}
+	wg.Wait()
+	return nil
+}
+
+func (c *AuthEventsConsumer) partitions(ctx context.Context) ([]kafka.Partition, error) {
+	var lastErr error
+	// kafka may not be ready yet when the service starts
+	for attempt := 0; attempt < 10; attempt++ {
+		conn, err := kafka.DialContext(ctx, "tcp", c.broker)
+		if err == nil {
+			partitions, err := conn.ReadPartitions(authEventsTopic)
+			conn.Close()
+			if err == nil && len(partitions) > 0 {
+				return partitions, nil
+			}
+			lastErr = err
+		} else {
+			lastErr = err
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(3 * time.Second):
+		}
+	}
+	return nil, lastErr
+}
+
+func (c *AuthEventsConsumer) consumePartition(ctx context.Context, partition int) {
+	reader := kafka.NewReader(kafka.ReaderConfig{
+		Brokers:     []string{c.broker},
+		Topic:       authEventsTopic,
+		Partition:   partition,
+		StartOffset: kafka.FirstOffset,
+	})
+	defer reader.Close()
+
+	// fall back to the full topic if the offset lookup fails
+	since := time.Now().Add(-c.tokenLifetime - replayMargin)
+	if err := reader.SetOffsetAt(ctx, since); err != nil {
+		log.Printf("Failed to seek auth-events partition %d to %s, replaying from start: %v", partition, since.Format(time.RFC3339), err)
+	}
+
+	for {
+		message, err := reader.ReadMessage(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			log.Printf("Failed to read auth event: %v", err)
+			continue
+		}
+		c.handle(message.Value)
+	}
+}
+
+func (c *AuthEventsConsumer) handle(value []byte) {
+	var event authEvent
+	if err := json.Unmarshal(value, &event); err != nil {
+		log.Printf("Failed to decode auth event: %v", err)
+		return
+	}
+
+	switch event.EventType {
+	case "token_revoked":
+		c.denylist.RevokeToken(event.Data.JTI, event.Data.ExpiresAt)
+	case "user_tokens_revoked":
+		c.denylist.RevokeUser(event.UserID, event.Data.RevokedBefore, event.Data.ExpiresAt)
+	}
+}
+
+func (c *AuthEventsConsumer) pruneLoop(ctx context.Context) {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			c.denylist.Prune()
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+
+	"shopping-service/internal/domain"
+
+	"github.com/segmentio/kafka-go"
+)
+
+const (
+	dataRequestServiceName = "shopping-service"
+	DataRequestEventsTopic = "data-request-events"
+)
+
+// UserDataHandler exports or erases everything stored about a user.
+type UserDataHandler interface {
+	ExportUserData(userID string) (*domain.UserData, error)
+	EraseUserData(userID string) error
+}
+
+type dataRequestResult struct {
+	RequestID string      `json:"request_id"`
+	Service   string      `json:"service"`
+	Type      string      `json:"type"`
+	Status    string      `json:"status"`
+	Error     string      `json:"error,omitempty"`
+	Data      interface{} `json:"data,omitempty"`
+}
+
+// DataRequestConsumer handles GDPR export and erasure requests published by
+// auth-service and reports the result on data-request-events. Unlike the
+// denylist consumer it uses a consumer group, so each request is handled once.
+type DataRequestConsumer struct {
+	reader   *kafka.Reader
+	results  *KafkaProducer
+	userData UserDataHandler
+}
+
+func NewDataRequestConsumer(broker string, results *KafkaProducer, userData UserDataHandler) *DataRequestConsumer {
+	return &DataRequestConsumer{
+		reader: kafka.NewReader(kafka.ReaderConfig{
+			Brokers: []string{broker},
+			Topic:   authEventsTopic,
+			GroupID: "shopping-service-data-requests",
+		}),
+		results:  results,
+		userData: userData,
+	}
+}
+
+func (c *DataRequestConsumer) StartConsuming(ctx context.Context) error {
+	log.Printf("Starting to consume data requests from '%s' topic", authEventsTopic)
+	defer c.reader.Close()
+
+	for {
+		message, err := c.reader.ReadMessage(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			log.Printf("Failed to read auth event: %v", err)
+			continue
+		}
+		c.handle(message.Value)
+	}
+}
+
+func (c *DataRequestConsumer) handle(value []byte) {
+	var event struct {
+		EventType string `json:"event_type"`
+		UserID    string `json:"user_id"`
+		Data      struct {
+			RequestID string `json:"request_id"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(value, &event); err != nil {
+		log.Printf("Failed to decode auth event: %v", err)
+		return
+	}
+
+	result := dataRequestResult{RequestID: event.Data.RequestID, Service: dataRequestServiceName, Status: "completed"}
+	var err error
+	switch event.EventType {
+	case "user_export_requested":
+		result.Type = "export"
+		result.Data, err = c.userData.ExportUserData(event.UserID)
+	case "user_erasure_requested":
+		result.Type = "erasure"
+		err = c.userData.EraseUserData(event.UserID)
+	default:
+		return
+	}
+	if err != nil {
+		log.Printf("Data request %s for user %s failed: %v", result.RequestID, event.UserID, err)
+		result.Status = "failed"
+		result.Error = err.Error()
+		result.Data = nil
+	}
+	if err := c.results.SendMessage(result); err != nil {
+		log.Printf("Failed to report data request %s: %v", result.RequestID, err)
+	}
+}
@@ -0,0 +1,77 @@
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+
+	"github.com/segmentio/kafka-go"
+)
+
+const paymentEventsTopic = "payment-events"
+
+// ReservationHandler completes the stock reservation of an order.
+type ReservationHandler interface {
+	Commit(orderID string) error
+	Release(orderID string) error
+}
+
+// PaymentEventsConsumer completes stock reservations when payment-service
+// reports the result of a payment: the reserved units are sold on success
+// and go back to stock on failure.
+type PaymentEventsConsumer struct {
+	reader       *kafka.Reader
+	reservations ReservationHandler
+}
+
+func NewPaymentEventsConsumer(broker string, reservations ReservationHandler) *PaymentEventsConsumer {
+	return &PaymentEventsConsumer{
+		reader: kafka.NewReader(kafka.ReaderConfig{
+			Brokers: []string{broker},
+			Topic:   paymentEventsTopic,
+			GroupID: "shopping-service-reservations",
+		}),
+		reservations: reservations,
+	}
+}
+
+func (c *PaymentEventsConsumer) StartConsuming(ctx context.Context) error {
+	log.Printf("Starting to consume from '%s' topic", paymentEventsTopic)
+	defer c.reader.Close()
+
+	for {
+		message, err := c.reader.ReadMessage(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			log.Printf("Failed to read payment event: %v", err)
+			continue
+		}
+		c.handle(message.Value)
+	}
+}
+
+func (c *PaymentEventsConsumer) handle(value []byte) {
+	var event struct {
+		EventType string `json:"event_type"`
+		OrderID   string `json:"order_id"`
+	}
+	if err := json.Unmarshal(value, &event); err != nil {
+		log.Printf("Failed to decode payment event: %v", err)
+		return
+	}
+
+	switch event.EventType {
+	case "payment_succeeded":
+		// A reservation that already expired is taken from stock again; if
+		// that fails, the order has to be checked by hand
+		if err := c.reservations.Commit(event.OrderID); err != nil {
+			log.Printf("⚠️ Could not commit reservation for paid order %s: %v", event.OrderID, err)
+		}
+	case "payment_failed":
+		if err := c.reservations.Release(event.OrderID); err != nil {
+			log.Printf("Could not release reservation for order %s: %v", event.OrderID, err)
+		}
+	}
+}
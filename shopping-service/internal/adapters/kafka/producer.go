@@ -9,6 +9,10 @@ import (
 	"github.com/segmentio/kafka-go"
 )
 
+// ProductEventsTopic receives product_updated, product_archived and
+// product_deleted events.
+const ProductEventsTopic = "product-events"
+
 type KafkaProducer struct {
 	writer *kafka.Writer
 }
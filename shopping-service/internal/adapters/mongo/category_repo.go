@@ -0,0 +1,128 @@
+package mongo
+
+import (
+	"context"
+	"log"
+	"shopping-service/internal/domain"
+	"shopping-service/internal/ports"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type CategoryRepo struct{ coll *mongo.Collection }
+
+func NewCategoryRepo(db *mongo.Database) ports.CategoryRepository {
+	repo := &CategoryRepo{coll: db.Collection("categories")}
+	if err := repo.ensureIndexes(); err != nil {
+		log.Printf("❌ Category indexes could not be created: %v", err)
+	}
+	return repo
+}
+
+func (r *CategoryRepo) ensureIndexes() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := r.coll.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "slug", Value: 1}}, Options: options.Index().SetName("slug_unique").SetUnique(true)},
+		{Keys: bson.D{{Key: "ancestors", Value: 1}}, Options: options.Index().SetName("ancestors")},
+	})
+	return err
+}
+
+func (r *CategoryRepo) Create(category *domain.Category) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	result, err := r.coll.InsertOne(ctx, category)
+	if mongo.IsDuplicateKeyError(err) {
+		return ports.ErrSlugTaken
+	}
+	if err != nil {
+		return err
+	}
+	category.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+func (r *CategoryRepo) FindAll() ([]domain.Category, error) {
+	return r.find(bson.M{}, options.Find().SetSort(bson.D{{Key: "name", Value: 1}}))
+}
+
+func (r *CategoryRepo) FindBySlug(slug string) (*domain.Category, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var category domain.Category
+	if err := r.coll.FindOne(ctx, bson.M{"slug": slug}).Decode(&category); err != nil {
+		return nil, err
+	}
+	return &category, nil
+}
+
+func (r *CategoryRepo) FindByIDs(ids []primitive.ObjectID) ([]domain.Category, error) {
+	return r.find(bson.M{"_id": bson.M{"$in": ids}}, nil)
+}
+
+func (r *CategoryRepo) FindDescendants(id primitive.ObjectID) ([]domain.Category, error) {
+	return r.find(bson.M{"ancestors": id}, nil)
+}
+
+func (r *CategoryRepo) Update(category *domain.Category) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	set := bson.M{"name": category.Name, "slug": category.Slug, "ancestors": category.Ancestors}
+	update := bson.M{"$set": set}
+	if category.ParentID != nil {
+		set["parent_id"] = category.ParentID
+	} else {
+		update["$unset"] = bson.M{"parent_id": ""}
+	}
+	result, err := r.coll.UpdateOne(ctx, bson.M{"_id": category.ID}, update)
+	if mongo.IsDuplicateKeyError(err) {
+		return ports.ErrSlugTaken
+	}
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+func (r *CategoryRepo) Delete(id primitive.ObjectID) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	result, err := r.coll.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+func (r *CategoryRepo) find(filter bson.M, opts *options.FindOptions) ([]domain.Category, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	cursor, err := r.coll.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	categories := []domain.Category{}
+	if err := cursor.All(ctx, &categories); err != nil {
+		return nil, err
+	}
+	return categories, nil
+}
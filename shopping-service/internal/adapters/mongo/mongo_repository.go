@@ -2,13 +2,22 @@ package mongo
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"regexp"
 	"shopping-service/internal/domain"
 	"shopping-service/internal/ports"
+	"sort"
+	"strings"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 	"log"
 )
 
@@ -17,9 +26,28 @@ type MongoRepository struct {
 }
 
 func NewMongoRepository(db *mongo.Database) ports.ProductRepository {
-	return &MongoRepository{
+	repo := &MongoRepository{
 		collection: db.Collection("products"),
 	}
+	if err := repo.ensureIndexes(); err != nil {
+		log.Printf("❌ Product indexes could not be created: %v", err)
+	}
+	return repo
+}
+
+// ensureIndexes creates the indexes for the sort orders of List and for
+// FindByUser. Existing indexes are left as they are.
+func (m *MongoRepository) ensureIndexes() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := m.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "price", Value: 1}, {Key: "_id", Value: 1}}, Options: options.Index().SetName("price_id")},
+		{Keys: bson.D{{Key: "name", Value: 1}, {Key: "_id", Value: 1}}, Options: options.Index().SetName("name_id")},
+		{Keys: bson.D{{Key: "user_id", Value: 1}}, Options: options.Index().SetName("user_id")},
+		{Keys: bson.D{{Key: "category_ids", Value: 1}}, Options: options.Index().SetName("category_ids")},
+	})
+	return err
 }
 
 func (m *MongoRepository) Create(product *domain.Product) error {
@@ -34,34 +62,149 @@ func (m *MongoRepository) Create(product *domain.Product) error {
 	return nil
 }
 
-func (m *MongoRepository) FindAll() ([]domain.Product, error) {
+// productCursor is the position after the last product of a page, encoded
+// as base64 JSON. Value is the sort field of that product (nil for "created").
+// Sort and Filter record the query the cursor was issued for, so it is not
+// applied to a different one.
+type productCursor struct {
+	Value  interface{} `json:"v,omitempty"`
+	ID     string      `json:"id"`
+	Sort   string      `json:"s"`
+	Filter string      `json:"f"`
+}
+
+// productFilterKey is a short fingerprint of the filters of a List query.
+func productFilterKey(filter domain.ProductFilter) string {
+	categories := make([]string, len(filter.CategoryIDs))
+	for i, id := range filter.CategoryIDs {
+		categories[i] = id.Hex()
+	}
+	sort.Strings(categories)
+	key, _ := json.Marshal([]interface{}{filter.Name, filter.MinPrice, filter.MaxPrice, categories})
+	sum := sha256.Sum256(key)
+	return hex.EncodeToString(sum[:8])
+}
+
+func (m *MongoRepository) List(filter domain.ProductFilter) (*domain.ProductPage, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	cursor, err := m.collection.Find(ctx, bson.M{})
+	query := bson.M{"archived": bson.M{"$ne": true}}
+	if filter.Name != "" {
+		query["name"] = bson.M{"$regex": regexp.QuoteMeta(filter.Name), "$options": "i"}
+	}
+	price := bson.M{}
+	if filter.MinPrice != nil {
+		price["$gte"] = *filter.MinPrice
+	}
+	if filter.MaxPrice != nil {
+		price["$lte"] = *filter.MaxPrice
+	}
+	if len(price) > 0 {
+		query["price"] = price
+	}
+	if len(filter.CategoryIDs) > 0 {
+		query["category_ids"] = bson.M{"$in": filter.CategoryIDs}
+	}
+
+	total, err := m.collection.CountDocuments(ctx, query)
 	if err != nil {
-		log.Println("❌ Fehler bei Find():", err)
+		log.Println("❌ Fehler bei CountDocuments():", err)
 		return nil, err
 	}
-	defer cursor.Close(ctx)
 
-	var products []domain.Product
-	for cursor.Next(ctx) {
-		var p domain.Product
-		if err := cursor.Decode(&p); err != nil {
-			log.Println("❌ Fehler beim Dekodieren:", err)
+	// Sortierung immer mit _id als zweitem Schlüssel, damit der Cursor
+	// auch bei gleichen Preisen oder Namen eindeutig ist
+	field, order := "_id", 1
+	if strings.HasPrefix(filter.Sort, "-") {
+		order = -1
+	}
+	switch strings.TrimPrefix(filter.Sort, "-") {
+	case domain.ProductSortName:
+		field = "name"
+	case domain.ProductSortPrice:
+		field = "price"
+	}
+	sortKeys := bson.D{{Key: field, Value: order}}
+	if field != "_id" {
+		sortKeys = append(sortKeys, bson.E{Key: "_id", Value: order})
+	}
+
+	filterKey := productFilterKey(filter)
+	if filter.Cursor != "" {
+		value, afterID, err := decodeProductCursor(filter.Cursor, filter.Sort, filterKey)
+		if err != nil {
 			return nil, err
 		}
-		products = append(products, p)
+		op := "$gt"
+		if order < 0 {
+			op = "$lt"
+		}
+		if field == "_id" {
+			query["_id"] = bson.M{op: afterID}
+		} else {
+			query["$or"] = bson.A{
+				bson.M{field: bson.M{op: value}},
+				bson.M{field: value, "_id": bson.M{op: afterID}},
+			}
+		}
+	}
+
+	opts := options.Find().SetSort(sortKeys).SetLimit(int64(filter.Limit) + 1)
+	cursor, err := m.collection.Find(ctx, query, opts)
+	if err != nil {
+		log.Println("❌ Fehler bei Find():", err)
+		return nil, err
 	}
+	defer cursor.Close(ctx)
 
-	if err := cursor.Err(); err != nil {
+	products := []domain.Product{}
+	if err := cursor.All(ctx, &products); err != nil {
 		log.Println("❌ Cursor-Fehler:", err)
 		return nil, err
 	}
 
-	log.Printf("✅ %d Produkte gefunden", len(products))
-	return products, nil
+	page := &domain.ProductPage{Total: total, Limit: filter.Limit}
+	if len(products) > filter.Limit {
+		products = products[:filter.Limit]
+		last := products[len(products)-1]
+		next := productCursor{ID: last.ID.Hex(), Sort: filter.Sort, Filter: filterKey}
+		switch field {
+		case "name":
+			next.Value = last.Name
+		case "price":
+			next.Value = last.Price
+		}
+		page.NextCursor = encodeProductCursor(next)
+	}
+	page.Products = products
+	return page, nil
+}
+
+func encodeProductCursor(c productCursor) string {
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// decodeProductCursor returns the position stored in s. The cursor must have
+// been issued for the same sort order and filters.
+func decodeProductCursor(s, sortOrder, filterKey string) (interface{}, primitive.ObjectID, error) {
+	var c productCursor
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err == nil {
+		err = json.Unmarshal(b, &c)
+	}
+	if err != nil {
+		return nil, primitive.NilObjectID, ports.ErrInvalidCursor
+	}
+	if c.Sort != sortOrder || c.Filter != filterKey {
+		return nil, primitive.NilObjectID, fmt.Errorf("%w: issued for a different sort or filter", ports.ErrInvalidCursor)
+	}
+	id, err := primitive.ObjectIDFromHex(c.ID)
+	if err != nil {
+		return nil, primitive.NilObjectID, ports.ErrInvalidCursor
+	}
+	return c.Value, id, nil
 }
 
 func (m *MongoRepository) FindByID(id string) (*domain.Product, error) {
@@ -89,3 +232,143 @@ func (m *MongoRepository) FindByID(id string) (*domain.Product, error) {
 	log.Printf("✅ Product found: %s - %.2f", product.Name, product.Price)
 	return &product, nil
 }
+
+func (m *MongoRepository) Update(product *domain.Product, setStock bool) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	set := bson.M{
+		"name":         product.Name,
+		"description":  product.Description,
+		"price":        product.Price,
+		"category_ids": product.CategoryIDs,
+		"archived":     product.Archived,
+		"archived_at":  product.ArchivedAt,
+		"updated_at":   product.UpdatedAt,
+	}
+	if setStock && product.Stock != nil {
+		set["stock"] = *product.Stock
+	}
+	result, err := m.collection.UpdateOne(ctx, bson.M{"_id": product.ID}, bson.M{"$set": set})
+	if err != nil {
+		log.Printf("❌ Error updating product %s: %v", product.ID.Hex(), err)
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+func (m *MongoRepository) Delete(id string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+	result, err := m.collection.DeleteOne(ctx, bson.M{"_id": objectID})
+	if err != nil {
+		log.Printf("❌ Error deleting product %s: %v", id, err)
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+func (m *MongoRepository) ReserveStock(id string, qty int) (bool, error) {
+	if qty <= 0 {
+		return false, fmt.Errorf("invalid reservation quantity %d", qty)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return false, err
+	}
+	// Bedingung und Änderung in einem Schritt, damit parallele Checkouts
+	// nicht mehr reservieren als vorhanden ist
+	filter := bson.M{"_id": objectID, "archived": bson.M{"$ne": true}, "stock": bson.M{"$gte": qty}}
+	result, err := m.collection.UpdateOne(ctx, filter, bson.M{"$inc": bson.M{"stock": -qty, "reserved": qty}})
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount == 1, nil
+}
+
+func (m *MongoRepository) ReleaseStock(id string, qty int) error {
+	return m.incStock(id, bson.M{"stock": qty, "reserved": -qty})
+}
+
+func (m *MongoRepository) CommitStock(id string, qty int) error {
+	return m.incStock(id, bson.M{"reserved": -qty})
+}
+
+func (m *MongoRepository) incStock(id string, inc bson.M) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+	result, err := m.collection.UpdateOne(ctx, bson.M{"_id": objectID, "stock": bson.M{"$exists": true}}, bson.M{"$inc": inc})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+func (m *MongoRepository) ReassignCategory(from primitive.ObjectID, to *primitive.ObjectID) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.M{"category_ids": from}
+	if to != nil {
+		if _, err := m.collection.UpdateMany(ctx, filter, bson.M{"$addToSet": bson.M{"category_ids": *to}}); err != nil {
+			return err
+		}
+	}
+	result, err := m.collection.UpdateMany(ctx, filter, bson.M{"$pull": bson.M{"category_ids": from}})
+	if err != nil {
+		return err
+	}
+	log.Printf("Removed category %s from %d products", from.Hex(), result.ModifiedCount)
+	return nil
+}
+
+func (m *MongoRepository) FindByUser(userID string) ([]domain.Product, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	cursor, err := m.collection.Find(ctx, bson.M{"user_id": userID})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	products := []domain.Product{}
+	if err := cursor.All(ctx, &products); err != nil {
+		return nil, err
+	}
+	return products, nil
+}
+
+func (m *MongoRepository) AnonymizeUser(userID string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	result, err := m.collection.UpdateMany(ctx, bson.M{"user_id": userID}, bson.M{"$set": bson.M{"user_id": ""}})
+	if err != nil {
+		return err
+	}
+	log.Printf("Anonymized %d products of user %s", result.ModifiedCount, userID)
+	return nil
+}
@@ -0,0 +1,78 @@
+package mongo
+
+import (
+	"errors"
+	"shopping-service/internal/domain"
+	"shopping-service/internal/ports"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestProductCursorRoundTrip(t *testing.T) {
+	id := primitive.NewObjectID()
+	filter := domain.ProductFilter{Name: "kamera", Sort: "-price"}
+	key := productFilterKey(filter)
+
+	cursor := encodeProductCursor(productCursor{Value: 19.99, ID: id.Hex(), Sort: filter.Sort, Filter: key})
+	value, afterID, err := decodeProductCursor(cursor, filter.Sort, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if value != 19.99 || afterID != id {
+		t.Errorf("decoded (%v, %s), want (19.99, %s)", value, afterID.Hex(), id.Hex())
+	}
+}
+
+func TestProductCursorRejectsOtherQuery(t *testing.T) {
+	minPrice, otherPrice := 10.0, 20.0
+	category, otherCategory := primitive.NewObjectID(), primitive.NewObjectID()
+	issued := domain.ProductFilter{Name: "kamera", MinPrice: &minPrice, CategoryIDs: []primitive.ObjectID{category}, Sort: "price"}
+	cursor := encodeProductCursor(productCursor{Value: 12.5, ID: primitive.NewObjectID().Hex(), Sort: issued.Sort, Filter: productFilterKey(issued)})
+
+	tests := []struct {
+		name   string
+		modify func(*domain.ProductFilter)
+	}{
+		{"other direction", func(f *domain.ProductFilter) { f.Sort = "-price" }},
+		{"other sort field", func(f *domain.ProductFilter) { f.Sort = "name" }},
+		{"other name", func(f *domain.ProductFilter) { f.Name = "stativ" }},
+		{"other min price", func(f *domain.ProductFilter) { f.MinPrice = &otherPrice }},
+		{"min price removed", func(f *domain.ProductFilter) { f.MinPrice = nil }},
+		{"max price added", func(f *domain.ProductFilter) { f.MaxPrice = &otherPrice }},
+		{"other category", func(f *domain.ProductFilter) { f.CategoryIDs = []primitive.ObjectID{otherCategory} }},
+		{"category added", func(f *domain.ProductFilter) { f.CategoryIDs = append(f.CategoryIDs, otherCategory) }},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			filter := issued
+			filter.CategoryIDs = append([]primitive.ObjectID{}, issued.CategoryIDs...)
+			tc.modify(&filter)
+			if _, _, err := decodeProductCursor(cursor, filter.Sort, productFilterKey(filter)); !errors.Is(err, ports.ErrInvalidCursor) {
+				t.Errorf("err = %v, want ErrInvalidCursor", err)
+			}
+		})
+	}
+}
+
+func TestProductFilterKeyIgnoresPageAndCategoryOrder(t *testing.T) {
+	a, b := primitive.NewObjectID(), primitive.NewObjectID()
+	first := domain.ProductFilter{CategoryIDs: []primitive.ObjectID{a, b}, Limit: 20, Cursor: "x"}
+	second := domain.ProductFilter{CategoryIDs: []primitive.ObjectID{b, a}, Limit: 50}
+	if productFilterKey(first) != productFilterKey(second) {
+		t.Error("filter key depends on category order, limit or cursor")
+	}
+}
+
+func TestDecodeProductCursorMalformed(t *testing.T) {
+	key := productFilterKey(domain.ProductFilter{})
+	for _, cursor := range []string{
+		"not base64!",
+		"bm90IGpzb24",
+		encodeProductCursor(productCursor{ID: "not-an-object-id", Sort: "created", Filter: key}),
+	} {
+		if _, _, err := decodeProductCursor(cursor, "created", key); !errors.Is(err, ports.ErrInvalidCursor) {
+			t.Errorf("decodeProductCursor(%q): err = %v, want ErrInvalidCursor", cursor, err)
+		}
+	}
+}
@@ -0,0 +1,312 @@
+package mongo
+
+import (
+	"context"
+	"html"
+	"log"
+	"regexp"
+	"shopping-service/internal/domain"
+	"shopping-service/internal/ports"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	// Terms of at least this length also match with one typo.
+	minFuzzyTermLength = 4
+	maxSearchTerms     = 10
+	maxFuzzyTermLength = 30
+	// maxFuzzyCandidates limits how many prefix/typo matches are ranked.
+	maxFuzzyCandidates = 200
+	snippetLength      = 160
+)
+
+// ProductSearch searches the products collection in two steps: the text
+// index finds whole (stemmed) words ranked by MongoDB's text score, then
+// products that only match by prefix or with a typo are appended, ranked by
+// how well and where they match.
+type ProductSearch struct {
+	collection *mongo.Collection
+}
+
+func NewProductSearch(db *mongo.Database) ports.ProductSearch {
+	search := &ProductSearch{collection: db.Collection("products")}
+	if err := search.ensureTextIndex(); err != nil {
+		log.Printf("❌ Product text index could not be created: %v", err)
+	}
+	return search
+}
+
+// ensureTextIndex creates the text index on name and description. A match in
+// the name counts five times as much. Stemming and stop words are German
+// like the rest of the shop.
+func (s *ProductSearch) ensureTextIndex() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := s.collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "name", Value: "text"}, {Key: "description", Value: "text"}},
+		Options: options.Index().
+			SetName("product_text").
+			SetWeights(bson.D{{Key: "name", Value: 5}, {Key: "description", Value: 1}}).
+			SetDefaultLanguage("german"),
+	})
+	return err
+}
+
+func (s *ProductSearch) Search(query string, limit int) ([]domain.ProductSearchHit, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	terms := searchTerms(query)
+	hits := []domain.ProductSearchHit{}
+	if len(terms) == 0 {
+		return hits, nil
+	}
+
+	textOpts := options.Find().
+		SetProjection(bson.M{"score": bson.M{"$meta": "textScore"}}).
+		SetSort(bson.M{"score": bson.M{"$meta": "textScore"}}).
+		SetLimit(int64(limit))
+	textMatches, err := s.find(ctx, bson.M{"$text": bson.M{"$search": query}, "archived": bson.M{"$ne": true}}, textOpts)
+	if err != nil {
+		log.Println("❌ Fehler bei der Textsuche:", err)
+		return nil, err
+	}
+	found := make([]primitive.ObjectID, 0, len(textMatches))
+	for _, p := range textMatches {
+		hits = append(hits, newSearchHit(p, terms))
+		found = append(found, p.ID)
+	}
+	if len(hits) >= limit {
+		return hits, nil
+	}
+
+	// Prefix- und Tippfehler-Treffer, die der Textindex nicht findet
+	var patterns bson.A
+	for _, term := range terms {
+		pattern := bson.M{"$regex": termPattern(term), "$options": "i"}
+		patterns = append(patterns, bson.M{"name": pattern}, bson.M{"description": pattern})
+	}
+	filter := bson.M{"$or": patterns, "archived": bson.M{"$ne": true}, "_id": bson.M{"$nin": found}}
+	fuzzyMatches, err := s.find(ctx, filter, options.Find().SetLimit(maxFuzzyCandidates))
+	if err != nil {
+		log.Println("❌ Fehler bei der Prefix-Suche:", err)
+		return nil, err
+	}
+	scores := make(map[primitive.ObjectID]int, len(fuzzyMatches))
+	for _, p := range fuzzyMatches {
+		scores[p.ID] = 5*matchScore(p.Name, terms) + matchScore(p.Description, terms)
+	}
+	sort.SliceStable(fuzzyMatches, func(i, j int) bool {
+		return scores[fuzzyMatches[i].ID] > scores[fuzzyMatches[j].ID]
+	})
+	for _, p := range fuzzyMatches {
+		if len(hits) >= limit {
+			break
+		}
+		if scores[p.ID] > 0 {
+			hits = append(hits, newSearchHit(p, terms))
+		}
+	}
+	return hits, nil
+}
+
+func (s *ProductSearch) find(ctx context.Context, filter bson.M, opts *options.FindOptions) ([]domain.Product, error) {
+	cursor, err := s.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var products []domain.Product
+	if err := cursor.All(ctx, &products); err != nil {
+		return nil, err
+	}
+	return products, nil
+}
+
+func newSearchHit(p domain.Product, terms [][]rune) domain.ProductSearchHit {
+	hit := domain.ProductSearchHit{Product: p, Highlights: map[string]string{}}
+	if snippet, ok := highlight(p.Name, terms); ok {
+		hit.Highlights["name"] = snippet
+	}
+	if snippet, ok := highlight(p.Description, terms); ok {
+		hit.Highlights["description"] = snippet
+	}
+	return hit
+}
+
+// searchTerms splits the query into lower-case words. Words excluded with
+// "-" are left out, the text index handles them.
+func searchTerms(query string) [][]rune {
+	var terms [][]rune
+	seen := map[string]bool{}
+	for _, field := range strings.Fields(strings.ToLower(query)) {
+		if strings.HasPrefix(field, "-") {
+			continue
+		}
+		for _, word := range strings.FieldsFunc(field, isNotWordRune) {
+			if seen[word] || len(terms) == maxSearchTerms {
+				continue
+			}
+			seen[word] = true
+			terms = append(terms, []rune(word))
+		}
+	}
+	return terms
+}
+
+func isNotWordRune(r rune) bool {
+	return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+}
+
+// termPattern matches words that start with term or, for longer terms, with
+// term with one character replaced, removed or inserted.
+func termPattern(term []rune) string {
+	alternatives := []string{regexp.QuoteMeta(string(term))}
+	if len(term) >= minFuzzyTermLength && len(term) <= maxFuzzyTermLength {
+		for i := range term {
+			head, tail := regexp.QuoteMeta(string(term[:i])), regexp.QuoteMeta(string(term[i+1:]))
+			alternatives = append(alternatives,
+				head+"."+tail,
+				head+tail,
+				head+"."+regexp.QuoteMeta(string(term[i:])),
+			)
+		}
+	}
+	return `(^|[^\p{L}\p{N}])(` + strings.Join(alternatives, "|") + `)`
+}
+
+// termMatch rates how well a word matches a search term: 3 for the same
+// word, 2 for a prefix, 1 for a prefix with one typo.
+func termMatch(word, term []rune) int {
+	switch {
+	case len(word) >= len(term) && string(word[:len(term)]) == string(term):
+		if len(word) == len(term) {
+			return 3
+		}
+		return 2
+	case len(term) >= minFuzzyTermLength:
+		for _, n := range []int{len(term) - 1, len(term), len(term) + 1} {
+			if n <= len(word) && editDistance(word[:n], term) <= 1 {
+				return 1
+			}
+		}
+	}
+	return 0
+}
+
+// matchScore sums the best match of every term in text.
+func matchScore(text string, terms [][]rune) int {
+	words := strings.FieldsFunc(strings.ToLower(text), isNotWordRune)
+	score := 0
+	for _, term := range terms {
+		best := 0
+		for _, word := range words {
+			best = max(best, termMatch([]rune(word), term))
+		}
+		score += best
+	}
+	return score
+}
+
+// highlight returns the part of text around the first match, at most
+// snippetLength characters, with matching words wrapped in <em>.
+func highlight(text string, terms [][]rune) (string, bool) {
+	runes := []rune(text)
+	type span struct{ start, end int }
+	var words []span
+	var matched []bool
+	first := -1
+	for i := 0; i < len(runes); {
+		if isNotWordRune(runes[i]) {
+			i++
+			continue
+		}
+		start := i
+		for i < len(runes) && !isNotWordRune(runes[i]) {
+			i++
+		}
+		word := []rune(strings.ToLower(string(runes[start:i])))
+		isMatch := false
+		for _, term := range terms {
+			if termMatch(word, term) > 0 {
+				isMatch = true
+				break
+			}
+		}
+		if isMatch && first < 0 {
+			first = start
+		}
+		words = append(words, span{start, i})
+		matched = append(matched, isMatch)
+	}
+	if first < 0 {
+		return "", false
+	}
+
+	from, to := 0, len(runes)
+	if len(runes) > snippetLength {
+		from = max(0, first-snippetLength/4)
+		to = min(len(runes), from+snippetLength)
+	}
+
+	var b strings.Builder
+	if from > 0 {
+		b.WriteString("…")
+	}
+	pos := from
+	for i, w := range words {
+		if w.end <= from || w.start >= to {
+			continue
+		}
+		// Angeschnittene Wörter am Rand weglassen
+		if w.start < from || w.end > to {
+			if w.start < from {
+				pos = w.end
+			}
+			continue
+		}
+		b.WriteString(html.EscapeString(string(runes[pos:w.start])))
+		word := html.EscapeString(string(runes[w.start:w.end]))
+		if matched[i] {
+			word = "<em>" + word + "</em>"
+		}
+		b.WriteString(word)
+		pos = w.end
+	}
+	if to < len(runes) {
+		b.WriteString("…")
+	} else {
+		b.WriteString(html.EscapeString(string(runes[pos:])))
+	}
+	return strings.TrimSpace(b.String()), true
+}
+
+func editDistance(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
@@ -0,0 +1,155 @@
+package mongo
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestSearchTerms(t *testing.T) {
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"Kamera", []string{"kamera"}},
+		{"  rote   Kamera ", []string{"rote", "kamera"}},
+		{"T-Shirt", []string{"t", "shirt"}},
+		{"kamera -stativ", []string{"kamera"}},
+		{"kamera Kamera KAMERA", []string{"kamera"}},
+		{"größe 42!", []string{"größe", "42"}},
+		{"-stativ", nil},
+		{"a b c d e f g h i j k l", []string{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j"}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.query, func(t *testing.T) {
+			var got []string
+			for _, term := range searchTerms(tc.query) {
+				got = append(got, string(term))
+			}
+			if !slices.Equal(got, tc.want) {
+				t.Errorf("searchTerms(%q) = %q, want %q", tc.query, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestTermMatch(t *testing.T) {
+	tests := []struct {
+		word, term string
+		want       int
+	}{
+		{"kamera", "kamera", 3},
+		{"kameras", "kamera", 2},
+		{"kameratasche", "kamera", 2},
+		{"kanera", "kamera", 1},
+		{"kmera", "kamera", 1},
+		{"kammera", "kamera", 1},
+		{"kanerastativ", "kamera", 1},
+		{"kmaera", "kamera", 0},
+		{"digitalkamera", "kamera", 0},
+		// short terms only match exactly or as a prefix
+		{"tx", "tv", 0},
+		{"tvs", "tv", 2},
+		{"größe", "große", 1},
+	}
+	for _, tc := range tests {
+		t.Run(tc.word+"/"+tc.term, func(t *testing.T) {
+			if got := termMatch([]rune(tc.word), []rune(tc.term)); got != tc.want {
+				t.Errorf("termMatch(%q, %q) = %d, want %d", tc.word, tc.term, got, tc.want)
+			}
+		})
+	}
+}
+
+// searchScore weighs the fields like the fallback ranking in Search.
+func searchScore(name, description string, terms [][]rune) int {
+	return 5*matchScore(name, terms) + matchScore(description, terms)
+}
+
+func TestMatchScoreRanking(t *testing.T) {
+	terms := searchTerms("rote kamera")
+	// expected order, best match first
+	products := []struct{ name, description string }{
+		{"Rote Kamera", ""},
+		{"Rote Kameras", ""},
+		{"Rote Kanera", ""},
+		{"Kamera", "in rot"},
+		{"Kameratasche", "passt zu jeder roten Kamera"},
+		{"Stativ", "für jede rote Kamera"},
+		{"Stativ", "für jede Kamera"},
+	}
+	for i := 1; i < len(products); i++ {
+		better := searchScore(products[i-1].name, products[i-1].description, terms)
+		worse := searchScore(products[i].name, products[i].description, terms)
+		if better <= worse {
+			t.Errorf("%q (%d) does not rank above %q (%d)", products[i-1].name+" "+products[i-1].description, better, products[i].name+" "+products[i].description, worse)
+		}
+	}
+	if score := searchScore("Stativ", "aus Aluminium", terms); score != 0 {
+		t.Errorf("unrelated product scored %d, want 0", score)
+	}
+}
+
+func TestTermPattern(t *testing.T) {
+	tests := []struct {
+		term, text string
+		want       bool
+	}{
+		{"kamera", "Rote Kamera", true},
+		{"kamera", "Kameratasche", true},
+		{"kamera", "Kanera", true},
+		{"kamera", "Kmera", true},
+		{"kamera", "Digitalkamera", false},
+		{"kamera", "Stativ", false},
+		{"tv", "TV-Gerät", true},
+		{"tv", "Tx", false},
+		{"a.b", "axb", false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.term+"/"+tc.text, func(t *testing.T) {
+			// MongoDB gets the same pattern with the "i" option
+			re := regexp.MustCompile("(?i)" + termPattern([]rune(tc.term)))
+			if got := re.MatchString(tc.text); got != tc.want {
+				t.Errorf("termPattern(%q) matches %q = %v, want %v", tc.term, tc.text, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestHighlight(t *testing.T) {
+	terms := searchTerms("kamera")
+	tests := []struct {
+		name, text, want string
+		wantOK           bool
+	}{
+		{"marks matches", "Rote Kamera mit Kameratasche", "Rote <em>Kamera</em> mit <em>Kameratasche</em>", true},
+		{"escapes html", "<b>Kamera</b> & Co", "&lt;b&gt;<em>Kamera</em>&lt;/b&gt; &amp; Co", true},
+		{"no match", "Stativ", "", false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, ok := highlight(tc.text, terms)
+			if got != tc.want || ok != tc.wantOK {
+				t.Errorf("highlight(%q) = (%q, %v), want (%q, %v)", tc.text, got, ok, tc.want, tc.wantOK)
+			}
+		})
+	}
+}
+
+func TestHighlightLongText(t *testing.T) {
+	text := strings.Repeat("Stativ ", 60) + "Kamera " + strings.Repeat("Zubehör ", 60)
+	got, ok := highlight(text, searchTerms("kamera"))
+	if !ok {
+		t.Fatal("no match found")
+	}
+	if !strings.HasPrefix(got, "…") || !strings.HasSuffix(got, "…") {
+		t.Errorf("snippet %q is not cut on both sides", got)
+	}
+	if !strings.Contains(got, "<em>Kamera</em>") {
+		t.Errorf("snippet %q misses the match", got)
+	}
+	if n := len([]rune(strings.NewReplacer("<em>", "", "</em>", "").Replace(got))); n > snippetLength+2 {
+		t.Errorf("snippet has %d characters, want at most %d", n, snippetLength+2)
+	}
+}
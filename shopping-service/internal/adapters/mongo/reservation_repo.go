@@ -0,0 +1,124 @@
+package mongo
+
+import (
+	"context"
+	"log"
+	"shopping-service/internal/domain"
+	"shopping-service/internal/ports"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type ReservationRepo struct{ coll *mongo.Collection }
+
+func NewReservationRepo(db *mongo.Database) ports.ReservationRepository {
+	repo := &ReservationRepo{coll: db.Collection("reservations")}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	_, err := repo.coll.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "expires_at", Value: 1}}, Options: options.Index().SetName("status_expires_at")},
+		{Keys: bson.D{{Key: "user_id", Value: 1}}, Options: options.Index().SetName("user_id")},
+	})
+	if err != nil {
+		log.Printf("❌ Reservation indexes could not be created: %v", err)
+	}
+	return repo
+}
+
+func (r *ReservationRepo) Create(reservation *domain.Reservation) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, err := r.coll.InsertOne(ctx, reservation)
+	return err
+}
+
+func (r *ReservationRepo) Close(id, status string) (*domain.Reservation, error) {
+	// Nur aus pending heraus, damit doppelte Events den Bestand nicht
+	// zweimal verändern
+	return r.transition(id, domain.ReservationPending, status)
+}
+
+func (r *ReservationRepo) CommitReleased(id string) (*domain.Reservation, error) {
+	return r.transition(id, domain.ReservationReleased, domain.ReservationCommitted)
+}
+
+func (r *ReservationRepo) transition(id, from, to string) (*domain.Reservation, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	filter := bson.M{"_id": id, "status": from}
+	update := bson.M{"$set": bson.M{"status": to, "closed_at": time.Now()}}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	var reservation domain.Reservation
+	err := r.coll.FindOneAndUpdate(ctx, filter, update, opts).Decode(&reservation)
+	if err == mongo.ErrNoDocuments {
+		return nil, ports.ErrReservationClosed
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &reservation, nil
+}
+
+func (r *ReservationRepo) HasPending(productID string) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	count, err := r.coll.CountDocuments(ctx,
+		bson.M{"status": domain.ReservationPending, "items.product_id": productID},
+		options.Count().SetLimit(1))
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (r *ReservationRepo) FindExpired(now time.Time) ([]domain.Reservation, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	cursor, err := r.coll.Find(ctx, bson.M{"status": domain.ReservationPending, "expires_at": bson.M{"$lt": now}})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	reservations := []domain.Reservation{}
+	if err := cursor.All(ctx, &reservations); err != nil {
+		return nil, err
+	}
+	return reservations, nil
+}
+
+func (r *ReservationRepo) FindByUser(userID string) ([]domain.Reservation, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	cursor, err := r.coll.Find(ctx, bson.M{"user_id": userID})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	reservations := []domain.Reservation{}
+	if err := cursor.All(ctx, &reservations); err != nil {
+		return nil, err
+	}
+	return reservations, nil
+}
+
+func (r *ReservationRepo) AnonymizeUser(userID string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	result, err := r.coll.UpdateMany(ctx, bson.M{"user_id": userID}, bson.M{"$set": bson.M{"user_id": ""}})
+	if err != nil {
+		return err
+	}
+	log.Printf("Anonymized %d reservations of user %s", result.ModifiedCount, userID)
+	return nil
+}
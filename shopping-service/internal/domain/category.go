@@ -0,0 +1,32 @@
+package domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Category is a node in the catalog tree. Ancestors lists the path from the
+// root down to the parent, so a subtree can be found with a single query.
+type Category struct {
+	ID        primitive.ObjectID   `json:"id" bson:"_id,omitempty"`
+	Name      string               `json:"name" bson:"name"`
+	Slug      string               `json:"slug" bson:"slug"`
+	ParentID  *primitive.ObjectID  `json:"parent_id,omitempty" bson:"parent_id,omitempty"`
+	Ancestors []primitive.ObjectID `json:"-" bson:"ancestors"`
+	CreatedAt time.Time            `json:"created_at" bson:"created_at"`
+}
+
+// CategoryNode is a category with its subcategories for GET /categories.
+type CategoryNode struct {
+	Category
+	Children []*CategoryNode `json:"children"`
+}
+
+// CategoryUpdate holds the fields to change; nil fields are left as they are.
+// An empty ParentSlug moves the category to the top level.
+type CategoryUpdate struct {
+	Name       *string `json:"name"`
+	Slug       *string `json:"slug"`
+	ParentSlug *string `json:"parent_slug"`
+}
@@ -1,12 +1,74 @@
 package domain
 
 import (
+	"time"
+
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 type Product struct {
-	ID     primitive.ObjectID `json:"id" bson:"_id,omitempty"`
-	Name   string             `json:"name" bson:"name"`
-	Price  float64            `json:"price" bson:"price"`
-	UserID string             `json:"user_id" bson:"user_id"`
+	ID          primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	Name        string             `json:"name" bson:"name"`
+	Description string             `json:"description" bson:"description,omitempty"`
+	Price       float64            `json:"price" bson:"price"`
+	UserID      string             `json:"user_id" bson:"user_id"`
+	// CategoryIDs are the categories the product is directly assigned to.
+	CategoryIDs []primitive.ObjectID `json:"category_ids" bson:"category_ids,omitempty"`
+	// Stock is the quantity that can still be sold, Reserved the quantity
+	// held for orders awaiting payment. Without Stock the product is not
+	// tracked and always available.
+	Stock    *int `json:"stock,omitempty" bson:"stock,omitempty"`
+	Reserved int  `json:"reserved,omitempty" bson:"reserved,omitempty"`
+	// Archived products are hidden from the catalog and can no longer be
+	// checked out, but stay in the database for existing orders.
+	Archived   bool       `json:"archived" bson:"archived,omitempty"`
+	ArchivedAt *time.Time `json:"archived_at,omitempty" bson:"archived_at,omitempty"`
+	UpdatedAt  *time.Time `json:"updated_at,omitempty" bson:"updated_at,omitempty"`
+}
+
+// ProductUpdate holds the fields to change; nil fields are left as they are.
+type ProductUpdate struct {
+	Name        *string               `json:"name"`
+	Description *string               `json:"description"`
+	Price       *float64              `json:"price"`
+	CategoryIDs *[]primitive.ObjectID `json:"category_ids"`
+	Stock       *int                  `json:"stock"`
+}
+
+// Sort orders for ProductFilter.Sort, prefixed with "-" for descending order.
+const (
+	ProductSortCreated = "created"
+	ProductSortName    = "name"
+	ProductSortPrice   = "price"
+)
+
+// ProductFilter narrows down the catalog; empty fields don't filter.
+type ProductFilter struct {
+	// Name matches case-insensitively anywhere in the product name.
+	Name     string
+	MinPrice *float64
+	MaxPrice *float64
+	// CategoryIDs matches products assigned to any of the categories.
+	CategoryIDs []primitive.ObjectID
+	Sort        string
+	// Cursor is the NextCursor of the previous page and must be used with
+	// the same filter and sort order.
+	Cursor string
+	Limit  int
+}
+
+// ProductPage is one page of the catalog. NextCursor is empty on the last page.
+type ProductPage struct {
+	Products   []Product `json:"products"`
+	Total      int64     `json:"total"`
+	Limit      int       `json:"limit"`
+	NextCursor string    `json:"next_cursor,omitempty"`
+}
+
+// ProductSearchHit is a search result. Highlights holds snippets of the
+// matching fields ("name", "description"), HTML-escaped and with the matched
+// words wrapped in <em>.
+type ProductSearchHit struct {
+	Product    Product           `json:"product"`
+	Highlights map[string]string `json:"highlights,omitempty"`
 }
@@ -0,0 +1,33 @@
+package domain
+
+// Address is a shipping address from the user's profile in auth-service.
+type Address struct {
+	ID         string `json:"id"`
+	Label      string `json:"label,omitempty"`
+	Name       string `json:"name"`
+	Street     string `json:"street"`
+	PostalCode string `json:"postal_code"`
+	City       string `json:"city"`
+	Country    string `json:"country"`
+	Default    bool   `json:"default"`
+}
+
+// Profile is the subset of GET /me that the checkout needs.
+type Profile struct {
+	ID                string    `json:"id"`
+	Email             string    `json:"email"`
+	DisplayName       string    `json:"display_name"`
+	Addresses         []Address `json:"addresses"`
+	PreferredCurrency string    `json:"preferred_currency"`
+}
+
+// Address returns the address with the given id, or the default address if id is empty.
+func (p *Profile) Address(id string) (*Address, bool) {
+	for i := range p.Addresses {
+		a := &p.Addresses[i]
+		if (id == "" && a.Default) || (id != "" && a.ID == id) {
+			return a, true
+		}
+	}
+	return nil, false
+}
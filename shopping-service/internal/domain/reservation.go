@@ -0,0 +1,28 @@
+package domain
+
+import "time"
+
+// Reservation states
+const (
+	ReservationPending   = "pending"
+	ReservationCommitted = "committed"
+	ReservationReleased  = "released"
+)
+
+// Reservation holds stock for an order from checkout until the payment
+// succeeds (committed) or fails or times out (released). Its ID is the
+// order ID used on the checkout and payment-events topics.
+type Reservation struct {
+	ID        string            `json:"order_id" bson:"_id"`
+	UserID    string            `json:"user_id" bson:"user_id"`
+	Items     []ReservationItem `json:"items" bson:"items"`
+	Status    string            `json:"status" bson:"status"`
+	CreatedAt time.Time         `json:"created_at" bson:"created_at"`
+	ExpiresAt time.Time         `json:"expires_at" bson:"expires_at"`
+	ClosedAt  *time.Time        `json:"closed_at,omitempty" bson:"closed_at,omitempty"`
+}
+
+type ReservationItem struct {
+	ProductID string `json:"product_id" bson:"product_id"`
+	Qty       int    `json:"qty" bson:"qty"`
+}
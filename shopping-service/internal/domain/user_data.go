@@ -0,0 +1,9 @@
+package domain
+
+// UserData is everything shopping-service stores about a user, as returned
+// for a data export request.
+type UserData struct {
+	Cart         Cart          `json:"cart"`
+	Products     []Product     `json:"products"`
+	Reservations []Reservation `json:"reservations"`
+}
@@ -0,0 +1,25 @@
+package ports
+
+import (
+	"errors"
+	"shopping-service/internal/domain"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ErrSlugTaken is returned by Create and Update if another category already
+// uses the slug.
+var ErrSlugTaken = errors.New("slug already in use")
+
+type CategoryRepository interface {
+	Create(category *domain.Category) error
+	// FindAll returns all categories sorted by name.
+	FindAll() ([]domain.Category, error)
+	FindBySlug(slug string) (*domain.Category, error)
+	FindByIDs(ids []primitive.ObjectID) ([]domain.Category, error)
+	// FindDescendants returns all categories below id, at any depth.
+	FindDescendants(id primitive.ObjectID) ([]domain.Category, error)
+	// Update stores name, slug, parent and ancestors.
+	Update(category *domain.Category) error
+	Delete(id primitive.ObjectID) error
+}
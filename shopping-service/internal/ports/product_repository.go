@@ -1,14 +1,49 @@
 package ports
 
-import "shopping-service/internal/domain"
+import (
+	"errors"
+	"shopping-service/internal/domain"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ErrInvalidCursor is returned by List for cursors it did not issue.
+var ErrInvalidCursor = errors.New("invalid cursor")
 
 type ProductRepository interface {
 	Create(product *domain.Product) error
-	FindAll() ([]domain.Product, error)
+	// List returns one page of the catalog without archived products. The
+	// filter is already validated and has a limit.
+	List(filter domain.ProductFilter) (*domain.ProductPage, error)
 	FindByID(id string) (*domain.Product, error)
+	// Update stores name, price and archive state of an existing product.
+	// With setStock its stock is written in the same update, which starts
+	// tracking the product; otherwise stock is left to the reservations.
+	Update(product *domain.Product, setStock bool) error
+	Delete(id string) error
+	// ReserveStock moves qty units from stock to reserved if enough are in
+	// stock and reports whether it did. qty must be positive.
+	ReserveStock(id string, qty int) (bool, error)
+	// ReleaseStock returns reserved units to stock.
+	ReleaseStock(id string, qty int) error
+	// CommitStock removes reserved units once they are sold.
+	CommitStock(id string, qty int) error
+	// ReassignCategory moves all products of category from to category to,
+	// or only removes the assignment if to is nil.
+	ReassignCategory(from primitive.ObjectID, to *primitive.ObjectID) error
+	// FindByUser returns the products created by a user.
+	FindByUser(userID string) ([]domain.Product, error)
+	// AnonymizeUser removes the creator from all products of a user.
+	AnonymizeUser(userID string) error
 }
 type ProductService interface {
 	CreateProduct(product *domain.Product) error
-	GetAllProducts() ([]domain.Product, error)
+	ListProducts(filter domain.ProductFilter) (*domain.ProductPage, error)
+	SearchProducts(query string, limit int) ([]domain.ProductSearchHit, error)
 	GetProductByID(id string) (*domain.Product, error)
+	// UpdateProduct, ArchiveProduct and DeleteProduct only allow the creator
+	// to change a product unless anyOwner is set.
+	UpdateProduct(id string, update domain.ProductUpdate, userID string, anyOwner bool) (*domain.Product, error)
+	ArchiveProduct(id, userID string, anyOwner bool) (*domain.Product, error)
+	DeleteProduct(id, userID string, anyOwner bool) (*domain.Product, error)
 }
@@ -0,0 +1,10 @@
+package ports
+
+import "shopping-service/internal/domain"
+
+// ProductSearch finds active products by free text. The Mongo adapter uses a
+// text index; a dedicated search engine can implement the same interface.
+type ProductSearch interface {
+	// Search returns at most limit products, the most relevant first.
+	Search(query string, limit int) ([]domain.ProductSearchHit, error)
+}
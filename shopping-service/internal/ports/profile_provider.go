@@ -0,0 +1,11 @@
+package ports
+
+import (
+	"context"
+	"shopping-service/internal/domain"
+)
+
+// ProfileProvider loads the profile of the user the bearer token belongs to.
+type ProfileProvider interface {
+	GetProfile(ctx context.Context, bearerToken string) (*domain.Profile, error)
+}
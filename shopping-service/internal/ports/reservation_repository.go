@@ -0,0 +1,28 @@
+package ports
+
+import (
+	"errors"
+	"shopping-service/internal/domain"
+	"time"
+)
+
+// ErrReservationClosed is returned by Close for unknown reservations and for
+// those that are no longer pending.
+var ErrReservationClosed = errors.New("reservation is not pending")
+
+type ReservationRepository interface {
+	Create(reservation *domain.Reservation) error
+	// Close sets the final status of a pending reservation and returns it.
+	Close(id, status string) (*domain.Reservation, error)
+	// CommitReleased marks a released reservation as committed and returns
+	// it, for orders paid after their stock was given back.
+	CommitReleased(id string) (*domain.Reservation, error)
+	// HasPending reports whether a pending reservation holds the product.
+	HasPending(productID string) (bool, error)
+	// FindExpired returns pending reservations that expired before now.
+	FindExpired(now time.Time) ([]domain.Reservation, error)
+	FindByUser(userID string) ([]domain.Reservation, error)
+	// AnonymizeUser removes the user reference; reservations stay for the
+	// stock history.
+	AnonymizeUser(userID string) error
+}
@@ -0,0 +1,228 @@
+package service
+
+import (
+	"errors"
+	"regexp"
+	"shopping-service/internal/domain"
+	"shopping-service/internal/ports"
+	"slices"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	ErrCategoryNotFound    = errors.New("category not found")
+	ErrInvalidCategory     = errors.New("category needs a name and a slug of lowercase letters, digits and dashes")
+	ErrCategoryCycle       = errors.New("category can't be moved below itself")
+	ErrCategoryExists      = errors.New("category slug already in use")
+	ErrCategoryHasChildren = errors.New("category has subcategories")
+)
+
+var slugPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// CategoryService manages the category tree. Products only reference the
+// categories they are assigned to directly, so moving a category takes its
+// whole subtree and all products in it along.
+type CategoryService struct {
+	categories ports.CategoryRepository
+	products   ports.ProductRepository
+}
+
+func NewCategoryService(categories ports.CategoryRepository, products ports.ProductRepository) *CategoryService {
+	return &CategoryService{categories: categories, products: products}
+}
+
+// Tree returns the top-level categories with their subcategories.
+func (s *CategoryService) Tree() ([]*domain.CategoryNode, error) {
+	categories, err := s.categories.FindAll()
+	if err != nil {
+		return nil, err
+	}
+	nodes := make(map[primitive.ObjectID]*domain.CategoryNode, len(categories))
+	for _, c := range categories {
+		nodes[c.ID] = &domain.CategoryNode{Category: c, Children: []*domain.CategoryNode{}}
+	}
+	roots := []*domain.CategoryNode{}
+	for _, c := range categories {
+		node := nodes[c.ID]
+		if c.ParentID != nil {
+			if parent, ok := nodes[*c.ParentID]; ok {
+				parent.Children = append(parent.Children, node)
+				continue
+			}
+		}
+		roots = append(roots, node)
+	}
+	return roots, nil
+}
+
+func (s *CategoryService) Get(slug string) (*domain.Category, error) {
+	category, err := s.categories.FindBySlug(slug)
+	if err != nil {
+		return nil, ErrCategoryNotFound
+	}
+	return category, nil
+}
+
+// SubtreeIDs returns the category and, with subtree set, all categories
+// below it, e.g. to list their products.
+func (s *CategoryService) SubtreeIDs(slug string, subtree bool) (*domain.Category, []primitive.ObjectID, error) {
+	category, err := s.Get(slug)
+	if err != nil {
+		return nil, nil, err
+	}
+	ids := []primitive.ObjectID{category.ID}
+	if !subtree {
+		return category, ids, nil
+	}
+	descendants, err := s.categories.FindDescendants(category.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, d := range descendants {
+		ids = append(ids, d.ID)
+	}
+	return category, ids, nil
+}
+
+// Create adds a category below parentSlug, or at the top level if it is
+// empty. Without slug, one is derived from the name.
+func (s *CategoryService) Create(name, slug, parentSlug string) (*domain.Category, error) {
+	category := &domain.Category{
+		Name:      strings.TrimSpace(name),
+		Slug:      strings.TrimSpace(slug),
+		Ancestors: []primitive.ObjectID{},
+		CreatedAt: time.Now(),
+	}
+	if category.Slug == "" {
+		category.Slug = Slugify(category.Name)
+	}
+	if category.Name == "" || !slugPattern.MatchString(category.Slug) {
+		return nil, ErrInvalidCategory
+	}
+	if parentSlug != "" {
+		parent, err := s.Get(parentSlug)
+		if err != nil {
+			return nil, err
+		}
+		setParent(category, parent)
+	}
+	if err := s.categories.Create(category); err != nil {
+		if errors.Is(err, ports.ErrSlugTaken) {
+			return nil, ErrCategoryExists
+		}
+		return nil, err
+	}
+	return category, nil
+}
+
+// Update renames a category or moves it to another parent. The ancestors of
+// all its subcategories are rewritten accordingly.
+func (s *CategoryService) Update(slug string, update domain.CategoryUpdate) (*domain.Category, error) {
+	category, err := s.Get(slug)
+	if err != nil {
+		return nil, err
+	}
+	if update.Name != nil {
+		category.Name = strings.TrimSpace(*update.Name)
+	}
+	if update.Slug != nil {
+		category.Slug = strings.TrimSpace(*update.Slug)
+	}
+	if category.Name == "" || !slugPattern.MatchString(category.Slug) {
+		return nil, ErrInvalidCategory
+	}
+
+	var descendants []domain.Category
+	moved := false
+	if update.ParentSlug != nil {
+		var parent *domain.Category
+		if *update.ParentSlug != "" {
+			if parent, err = s.Get(*update.ParentSlug); err != nil {
+				return nil, err
+			}
+			if parent.ID == category.ID || slices.Contains(parent.Ancestors, category.ID) {
+				return nil, ErrCategoryCycle
+			}
+		}
+		if descendants, err = s.categories.FindDescendants(category.ID); err != nil {
+			return nil, err
+		}
+		oldDepth := len(category.Ancestors)
+		setParent(category, parent)
+		moved = true
+
+		// Pfad oberhalb der verschobenen Kategorie ersetzen, darunter bleibt er gleich
+		for i := range descendants {
+			below := descendants[i].Ancestors[oldDepth+1:]
+			path := append(slices.Clone(category.Ancestors), category.ID)
+			descendants[i].Ancestors = append(path, below...)
+		}
+	}
+
+	if err := s.categories.Update(category); err != nil {
+		if errors.Is(err, ports.ErrSlugTaken) {
+			return nil, ErrCategoryExists
+		}
+		return nil, err
+	}
+	if moved {
+		for i := range descendants {
+			if err := s.categories.Update(&descendants[i]); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return category, nil
+}
+
+// Delete removes a category without subcategories. Its products are assigned
+// to the parent category instead, so they stay in the same part of the tree.
+func (s *CategoryService) Delete(slug string) error {
+	category, err := s.Get(slug)
+	if err != nil {
+		return err
+	}
+	descendants, err := s.categories.FindDescendants(category.ID)
+	if err != nil {
+		return err
+	}
+	if len(descendants) > 0 {
+		return ErrCategoryHasChildren
+	}
+	if err := s.products.ReassignCategory(category.ID, category.ParentID); err != nil {
+		return err
+	}
+	return s.categories.Delete(category.ID)
+}
+
+// Slugify derives a URL-friendly slug from a name, e.g. "Küche & Haushalt"
+// becomes "kueche-haushalt".
+func Slugify(name string) string {
+	replacer := strings.NewReplacer("ä", "ae", "ö", "oe", "ü", "ue", "ß", "ss")
+	var b strings.Builder
+	dash := false
+	for _, r := range replacer.Replace(strings.ToLower(name)) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+			dash = false
+		} else if !dash && b.Len() > 0 {
+			b.WriteByte('-')
+			dash = true
+		}
+	}
+	return strings.TrimSuffix(b.String(), "-")
+}
+
+func setParent(category, parent *domain.Category) {
+	if parent == nil {
+		category.ParentID = nil
+		category.Ancestors = []primitive.ObjectID{}
+		return
+	}
+	id := parent.ID
+	category.ParentID = &id
+	category.Ancestors = append(slices.Clone(parent.Ancestors), parent.ID)
+}
@@ -0,0 +1,174 @@
+package service
+
+import (
+	"errors"
+	"shopping-service/internal/domain"
+	"slices"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// fakeCategories keeps the category tree in memory.
+type fakeCategories struct {
+	categories map[primitive.ObjectID]*domain.Category
+}
+
+func (f *fakeCategories) Create(category *domain.Category) error {
+	category.ID = primitive.NewObjectID()
+	copied := *category
+	f.categories[category.ID] = &copied
+	return nil
+}
+
+func (f *fakeCategories) FindAll() ([]domain.Category, error) {
+	var all []domain.Category
+	for _, c := range f.categories {
+		all = append(all, *c)
+	}
+	return all, nil
+}
+
+func (f *fakeCategories) FindBySlug(slug string) (*domain.Category, error) {
+	for _, c := range f.categories {
+		if c.Slug == slug {
+			copied := *c
+			copied.Ancestors = slices.Clone(c.Ancestors)
+			return &copied, nil
+		}
+	}
+	return nil, errors.New("not found")
+}
+
+func (f *fakeCategories) FindByIDs(ids []primitive.ObjectID) ([]domain.Category, error) {
+	var found []domain.Category
+	for _, id := range ids {
+		if c, ok := f.categories[id]; ok {
+			found = append(found, *c)
+		}
+	}
+	return found, nil
+}
+
+func (f *fakeCategories) FindDescendants(id primitive.ObjectID) ([]domain.Category, error) {
+	var descendants []domain.Category
+	for _, c := range f.categories {
+		if slices.Contains(c.Ancestors, id) {
+			copied := *c
+			copied.Ancestors = slices.Clone(c.Ancestors)
+			descendants = append(descendants, copied)
+		}
+	}
+	return descendants, nil
+}
+
+func (f *fakeCategories) Update(category *domain.Category) error {
+	copied := *category
+	copied.Ancestors = slices.Clone(category.Ancestors)
+	f.categories[category.ID] = &copied
+	return nil
+}
+
+func (f *fakeCategories) Delete(id primitive.ObjectID) error {
+	delete(f.categories, id)
+	return nil
+}
+
+// newTestCategoryTree builds electronics > phones > android and books.
+func newTestCategoryTree(t *testing.T) (*CategoryService, *fakeCategories) {
+	t.Helper()
+	categories := &fakeCategories{categories: map[primitive.ObjectID]*domain.Category{}}
+	s := NewCategoryService(categories, nil)
+	for _, c := range []struct{ name, parent string }{
+		{"electronics", ""},
+		{"phones", "electronics"},
+		{"android", "phones"},
+		{"books", ""},
+	} {
+		if _, err := s.Create(c.name, "", c.parent); err != nil {
+			t.Fatalf("Create(%s): %v", c.name, err)
+		}
+	}
+	return s, categories
+}
+
+// ancestorSlugs returns the path above a category as slugs, top level first.
+func ancestorSlugs(t *testing.T, categories *fakeCategories, slug string) []string {
+	t.Helper()
+	category, err := categories.FindBySlug(slug)
+	if err != nil {
+		t.Fatalf("category %s missing", slug)
+	}
+	slugs := []string{}
+	for _, id := range category.Ancestors {
+		slugs = append(slugs, categories.categories[id].Slug)
+	}
+	return slugs
+}
+
+func TestCategoryMoveRejectsCycles(t *testing.T) {
+	tests := []struct {
+		name   string
+		slug   string
+		parent string
+	}{
+		{"below itself", "electronics", "electronics"},
+		{"below its child", "electronics", "phones"},
+		{"below its grandchild", "electronics", "android"},
+		{"middle node below its child", "phones", "android"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			s, categories := newTestCategoryTree(t)
+			parent := tc.parent
+			if _, err := s.Update(tc.slug, domain.CategoryUpdate{ParentSlug: &parent}); !errors.Is(err, ErrCategoryCycle) {
+				t.Fatalf("Update: err = %v, want ErrCategoryCycle", err)
+			}
+			// the tree is left unchanged
+			if got := ancestorSlugs(t, categories, "android"); !slices.Equal(got, []string{"electronics", "phones"}) {
+				t.Errorf("android ancestors = %v after rejected move", got)
+			}
+		})
+	}
+}
+
+func TestCategoryMoveRewritesAncestors(t *testing.T) {
+	tests := []struct {
+		name   string
+		slug   string
+		parent string
+		want   map[string][]string
+	}{
+		{"subtree to another parent", "phones", "books", map[string][]string{
+			"phones":  {"books"},
+			"android": {"books", "phones"},
+		}},
+		{"leaf to top level", "android", "", map[string][]string{
+			"android": {},
+			"phones":  {"electronics"},
+		}},
+		{"top level below another", "electronics", "books", map[string][]string{
+			"electronics": {"books"},
+			"phones":      {"books", "electronics"},
+			"android":     {"books", "electronics", "phones"},
+		}},
+		{"same parent", "phones", "electronics", map[string][]string{
+			"phones":  {"electronics"},
+			"android": {"electronics", "phones"},
+		}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			s, categories := newTestCategoryTree(t)
+			parent := tc.parent
+			if _, err := s.Update(tc.slug, domain.CategoryUpdate{ParentSlug: &parent}); err != nil {
+				t.Fatalf("Update: %v", err)
+			}
+			for slug, want := range tc.want {
+				if got := ancestorSlugs(t, categories, slug); !slices.Equal(got, want) {
+					t.Errorf("%s ancestors = %v, want %v", slug, got, want)
+				}
+			}
+		})
+	}
+}
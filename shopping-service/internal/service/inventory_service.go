@@ -0,0 +1,221 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"shopping-service/internal/domain"
+	"shopping-service/internal/ports"
+	"time"
+)
+
+var (
+	ErrReservationNotPending = errors.New("reservation is already committed or released")
+	ErrInvalidQuantity       = errors.New("quantity must be greater than 0")
+)
+
+// OutOfStockError is returned when fewer units are in stock than requested.
+type OutOfStockError struct {
+	ProductID string
+	Requested int
+	Available int
+}
+
+func (e *OutOfStockError) Error() string {
+	return fmt.Sprintf("product %s is out of stock: requested %d, available %d", e.ProductID, e.Requested, e.Available)
+}
+
+// InventoryService tracks stock and holds it for orders awaiting payment.
+// Products without a stock level are not tracked and never run out.
+type InventoryService struct {
+	products     ports.ProductRepository
+	reservations ports.ReservationRepository
+	ttl          time.Duration
+}
+
+// NewInventoryService creates an InventoryService whose reservations are
+// released after ttl unless the payment completes earlier.
+func NewInventoryService(products ports.ProductRepository, reservations ports.ReservationRepository, ttl time.Duration) *InventoryService {
+	return &InventoryService{products: products, reservations: reservations, ttl: ttl}
+}
+
+// CheckAvailable returns an OutOfStockError if qty units of the product
+// can't be sold right now.
+func (s *InventoryService) CheckAvailable(productID string, qty int) error {
+	product, err := s.products.FindByID(productID)
+	if err != nil || product.Archived {
+		return ErrProductNotFound
+	}
+	if product.Stock != nil && *product.Stock < qty {
+		return &OutOfStockError{ProductID: productID, Requested: qty, Available: *product.Stock}
+	}
+	return nil
+}
+
+// Reserve holds the items of an order. Either all tracked items are
+// reserved or none; on failure the first product that is short is reported
+// as OutOfStockError.
+func (s *InventoryService) Reserve(orderID, userID string, items []domain.CartItem) (*domain.Reservation, error) {
+	// Mengen pro Produkt zusammenfassen, falls ein Produkt mehrfach vorkommt
+	var reserve []domain.ReservationItem
+	index := map[string]int{}
+	for _, item := range items {
+		// Negative Mengen würden den Bestand beim Reservieren erhöhen
+		if item.Qty <= 0 {
+			return nil, ErrInvalidQuantity
+		}
+		if i, ok := index[item.ProductID]; ok {
+			reserve[i].Qty += item.Qty
+			continue
+		}
+		index[item.ProductID] = len(reserve)
+		reserve = append(reserve, domain.ReservationItem{ProductID: item.ProductID, Qty: item.Qty})
+	}
+
+	var reserved []domain.ReservationItem
+	for _, item := range reserve {
+		product, err := s.products.FindByID(item.ProductID)
+		if err != nil || product.Archived {
+			s.releaseItems(orderID, reserved)
+			return nil, ErrProductNotFound
+		}
+		if product.Stock == nil {
+			continue
+		}
+		ok, err := s.products.ReserveStock(item.ProductID, item.Qty)
+		if err != nil {
+			s.releaseItems(orderID, reserved)
+			return nil, err
+		}
+		if !ok {
+			s.releaseItems(orderID, reserved)
+			return nil, s.outOfStock(item)
+		}
+		reserved = append(reserved, item)
+	}
+
+	now := time.Now()
+	reservation := &domain.Reservation{
+		ID:        orderID,
+		UserID:    userID,
+		Items:     reserved,
+		Status:    domain.ReservationPending,
+		CreatedAt: now,
+		ExpiresAt: now.Add(s.ttl),
+	}
+	if reservation.Items == nil {
+		reservation.Items = []domain.ReservationItem{}
+	}
+	if err := s.reservations.Create(reservation); err != nil {
+		s.releaseItems(orderID, reserved)
+		return nil, err
+	}
+	return reservation, nil
+}
+
+// Commit marks the reserved units as sold once the order is paid. If the
+// reservation was already released, e.g. because the payment arrived after
+// the TTL, the units are taken from stock again; products that are short by
+// then are reported as OutOfStockError and need to be handled by hand.
+func (s *InventoryService) Commit(orderID string) error {
+	reservation, err := s.close(orderID, domain.ReservationCommitted)
+	if errors.Is(err, ErrReservationNotPending) {
+		return s.commitReleased(orderID)
+	}
+	if err != nil {
+		return err
+	}
+	for _, item := range reservation.Items {
+		if err := s.products.CommitStock(item.ProductID, item.Qty); err != nil {
+			log.Printf("❌ Could not commit %d units of product %s for order %s: %v", item.Qty, item.ProductID, orderID, err)
+		}
+	}
+	return nil
+}
+
+func (s *InventoryService) commitReleased(orderID string) error {
+	reservation, err := s.reservations.CommitReleased(orderID)
+	if errors.Is(err, ports.ErrReservationClosed) {
+		return ErrReservationNotPending
+	}
+	if err != nil {
+		return err
+	}
+
+	var shortage error
+	for _, item := range reservation.Items {
+		ok, err := s.products.ReserveStock(item.ProductID, item.Qty)
+		if err == nil && !ok {
+			err = s.outOfStock(item)
+		}
+		if err != nil {
+			log.Printf("❌ Could not take %d units of product %s again for late payment of order %s: %v", item.Qty, item.ProductID, orderID, err)
+			if shortage == nil {
+				shortage = err
+			}
+			continue
+		}
+		if err := s.products.CommitStock(item.ProductID, item.Qty); err != nil {
+			log.Printf("❌ Could not commit %d units of product %s for order %s: %v", item.Qty, item.ProductID, orderID, err)
+		}
+	}
+	return shortage
+}
+
+// Release returns the reserved units to stock, e.g. after a failed payment.
+func (s *InventoryService) Release(orderID string) error {
+	reservation, err := s.close(orderID, domain.ReservationReleased)
+	if err != nil {
+		return err
+	}
+	s.releaseItems(orderID, reservation.Items)
+	return nil
+}
+
+// ReleaseExpired releases all reservations whose payment did not complete
+// in time and returns how many it released.
+func (s *InventoryService) ReleaseExpired() (int, error) {
+	expired, err := s.reservations.FindExpired(time.Now())
+	if err != nil {
+		return 0, err
+	}
+	released := 0
+	for _, reservation := range expired {
+		err := s.Release(reservation.ID)
+		if errors.Is(err, ErrReservationNotPending) {
+			// In der Zwischenzeit bezahlt oder schon freigegeben
+			continue
+		}
+		if err != nil {
+			return released, err
+		}
+		released++
+	}
+	return released, nil
+}
+
+func (s *InventoryService) close(orderID, status string) (*domain.Reservation, error) {
+	reservation, err := s.reservations.Close(orderID, status)
+	if errors.Is(err, ports.ErrReservationClosed) {
+		return nil, ErrReservationNotPending
+	}
+	return reservation, err
+}
+
+// outOfStock reports an item that could not be reserved with the stock
+// level at this moment.
+func (s *InventoryService) outOfStock(item domain.ReservationItem) *OutOfStockError {
+	available := 0
+	if current, err := s.products.FindByID(item.ProductID); err == nil && current.Stock != nil {
+		available = *current.Stock
+	}
+	return &OutOfStockError{ProductID: item.ProductID, Requested: item.Qty, Available: available}
+}
+
+func (s *InventoryService) releaseItems(orderID string, items []domain.ReservationItem) {
+	for _, item := range items {
+		if err := s.products.ReleaseStock(item.ProductID, item.Qty); err != nil {
+			log.Printf("❌ Could not release %d units of product %s for order %s: %v", item.Qty, item.ProductID, orderID, err)
+		}
+	}
+}
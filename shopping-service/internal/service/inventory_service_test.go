@@ -0,0 +1,261 @@
+package service
+
+import (
+	"errors"
+	"shopping-service/internal/domain"
+	"shopping-service/internal/ports"
+	"testing"
+	"time"
+)
+
+// fakeProducts keeps products in memory. Methods the inventory doesn't use
+// panic through the nil embedded interface.
+type fakeProducts struct {
+	ports.ProductRepository
+	products map[string]*domain.Product
+}
+
+func (f *fakeProducts) FindByID(id string) (*domain.Product, error) {
+	p, ok := f.products[id]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	copied := *p
+	return &copied, nil
+}
+
+func (f *fakeProducts) ReserveStock(id string, qty int) (bool, error) {
+	p, ok := f.products[id]
+	if !ok || p.Archived || p.Stock == nil || *p.Stock < qty {
+		return false, nil
+	}
+	*p.Stock -= qty
+	p.Reserved += qty
+	return true, nil
+}
+
+func (f *fakeProducts) ReleaseStock(id string, qty int) error {
+	p := f.products[id]
+	*p.Stock += qty
+	p.Reserved -= qty
+	return nil
+}
+
+func (f *fakeProducts) CommitStock(id string, qty int) error {
+	f.products[id].Reserved -= qty
+	return nil
+}
+
+type fakeReservations struct {
+	ports.ReservationRepository
+	reservations map[string]*domain.Reservation
+}
+
+func (f *fakeReservations) Create(r *domain.Reservation) error {
+	copied := *r
+	f.reservations[r.ID] = &copied
+	return nil
+}
+
+func (f *fakeReservations) Close(id, status string) (*domain.Reservation, error) {
+	return f.transition(id, domain.ReservationPending, status)
+}
+
+func (f *fakeReservations) CommitReleased(id string) (*domain.Reservation, error) {
+	return f.transition(id, domain.ReservationReleased, domain.ReservationCommitted)
+}
+
+func (f *fakeReservations) transition(id, from, to string) (*domain.Reservation, error) {
+	r, ok := f.reservations[id]
+	if !ok || r.Status != from {
+		return nil, ports.ErrReservationClosed
+	}
+	r.Status = to
+	copied := *r
+	return &copied, nil
+}
+
+func (f *fakeReservations) FindExpired(now time.Time) ([]domain.Reservation, error) {
+	var expired []domain.Reservation
+	for _, r := range f.reservations {
+		if r.Status == domain.ReservationPending && r.ExpiresAt.Before(now) {
+			expired = append(expired, *r)
+		}
+	}
+	return expired, nil
+}
+
+func stock(n int) *int { return &n }
+
+func newTestInventory(products map[string]*domain.Product) (*InventoryService, *fakeProducts, *fakeReservations) {
+	p := &fakeProducts{products: products}
+	r := &fakeReservations{reservations: map[string]*domain.Reservation{}}
+	return NewInventoryService(p, r, 15*time.Minute), p, r
+}
+
+func assertStock(t *testing.T, products *fakeProducts, id string, wantStock, wantReserved int) {
+	t.Helper()
+	p := products.products[id]
+	if *p.Stock != wantStock || p.Reserved != wantReserved {
+		t.Errorf("product %s: stock %d, reserved %d; want %d, %d", id, *p.Stock, p.Reserved, wantStock, wantReserved)
+	}
+}
+
+func TestReserveMergesDuplicateItems(t *testing.T) {
+	inv, products, _ := newTestInventory(map[string]*domain.Product{
+		"a": {Stock: stock(10)},
+		"u": {},
+	})
+
+	reservation, err := inv.Reserve("order-1", "user-1", []domain.CartItem{
+		{ProductID: "a", Qty: 2},
+		{ProductID: "u", Qty: 1},
+		{ProductID: "a", Qty: 3},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// untracked products are not part of the reservation
+	want := []domain.ReservationItem{{ProductID: "a", Qty: 5}}
+	if len(reservation.Items) != 1 || reservation.Items[0] != want[0] {
+		t.Errorf("items = %+v, want %+v", reservation.Items, want)
+	}
+	assertStock(t, products, "a", 5, 5)
+}
+
+func TestReserveRollsBackOnFailure(t *testing.T) {
+	tests := []struct {
+		name    string
+		items   []domain.CartItem
+		wantErr error
+	}{
+		{"second product short", []domain.CartItem{{ProductID: "a", Qty: 2}, {ProductID: "b", Qty: 3}}, &OutOfStockError{ProductID: "b", Requested: 3, Available: 1}},
+		{"merged quantity short", []domain.CartItem{{ProductID: "a", Qty: 2}, {ProductID: "b", Qty: 1}, {ProductID: "b", Qty: 1}}, &OutOfStockError{ProductID: "b", Requested: 2, Available: 1}},
+		{"unknown product", []domain.CartItem{{ProductID: "a", Qty: 2}, {ProductID: "missing", Qty: 1}}, ErrProductNotFound},
+		{"archived product", []domain.CartItem{{ProductID: "a", Qty: 2}, {ProductID: "archived", Qty: 1}}, ErrProductNotFound},
+		{"zero quantity", []domain.CartItem{{ProductID: "a", Qty: 2}, {ProductID: "b", Qty: 0}}, ErrInvalidQuantity},
+		{"negative quantity", []domain.CartItem{{ProductID: "a", Qty: -2}}, ErrInvalidQuantity},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			inv, products, reservations := newTestInventory(map[string]*domain.Product{
+				"a":        {Stock: stock(5)},
+				"b":        {Stock: stock(1)},
+				"archived": {Stock: stock(5), Archived: true},
+			})
+
+			_, err := inv.Reserve("order-1", "user-1", tc.items)
+			var outOfStock *OutOfStockError
+			if want, ok := tc.wantErr.(*OutOfStockError); ok {
+				if !errors.As(err, &outOfStock) || *outOfStock != *want {
+					t.Fatalf("err = %v, want %v", err, want)
+				}
+			} else if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tc.wantErr)
+			}
+
+			assertStock(t, products, "a", 5, 0)
+			assertStock(t, products, "b", 1, 0)
+			if len(reservations.reservations) != 0 {
+				t.Errorf("reservation stored despite error")
+			}
+		})
+	}
+}
+
+func TestCommitAndReleaseAreIdempotent(t *testing.T) {
+	tests := []struct {
+		name         string
+		close        func(*InventoryService, string) error
+		wantStock    int
+		wantReserved int
+	}{
+		{"commit", (*InventoryService).Commit, 7, 0},
+		{"release", (*InventoryService).Release, 10, 0},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			inv, products, _ := newTestInventory(map[string]*domain.Product{"a": {Stock: stock(10)}})
+			if _, err := inv.Reserve("order-1", "user-1", []domain.CartItem{{ProductID: "a", Qty: 3}}); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := tc.close(inv, "order-1"); err != nil {
+				t.Fatalf("first call: %v", err)
+			}
+			if err := tc.close(inv, "order-1"); !errors.Is(err, ErrReservationNotPending) {
+				t.Errorf("second call: err = %v, want ErrReservationNotPending", err)
+			}
+			assertStock(t, products, "a", tc.wantStock, tc.wantReserved)
+		})
+	}
+}
+
+func TestCommitAfterRelease(t *testing.T) {
+	inv, products, _ := newTestInventory(map[string]*domain.Product{"a": {Stock: stock(10)}})
+	if _, err := inv.Reserve("order-1", "user-1", []domain.CartItem{{ProductID: "a", Qty: 3}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := inv.Release("order-1"); err != nil {
+		t.Fatal(err)
+	}
+
+	// a late payment takes the units from stock again, but only once
+	if err := inv.Commit("order-1"); err != nil {
+		t.Fatalf("late commit: %v", err)
+	}
+	assertStock(t, products, "a", 7, 0)
+	if err := inv.Commit("order-1"); !errors.Is(err, ErrReservationNotPending) {
+		t.Errorf("repeated late commit: err = %v, want ErrReservationNotPending", err)
+	}
+	assertStock(t, products, "a", 7, 0)
+}
+
+func TestCommitAfterReleaseOutOfStock(t *testing.T) {
+	inv, products, reservations := newTestInventory(map[string]*domain.Product{"a": {Stock: stock(10)}})
+	if _, err := inv.Reserve("order-1", "user-1", []domain.CartItem{{ProductID: "a", Qty: 3}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := inv.Release("order-1"); err != nil {
+		t.Fatal(err)
+	}
+	*products.products["a"].Stock = 1
+
+	var outOfStock *OutOfStockError
+	if err := inv.Commit("order-1"); !errors.As(err, &outOfStock) {
+		t.Fatalf("err = %v, want *OutOfStockError", err)
+	}
+	assertStock(t, products, "a", 1, 0)
+	if status := reservations.reservations["order-1"].Status; status != domain.ReservationCommitted {
+		t.Errorf("status = %s, want %s", status, domain.ReservationCommitted)
+	}
+}
+
+func TestReleaseExpired(t *testing.T) {
+	inv, products, reservations := newTestInventory(map[string]*domain.Product{"a": {Stock: stock(10)}})
+	for _, orderID := range []string{"expired", "paid", "current"} {
+		if _, err := inv.Reserve(orderID, "user-1", []domain.CartItem{{ProductID: "a", Qty: 2}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	past := time.Now().Add(-time.Minute)
+	reservations.reservations["expired"].ExpiresAt = past
+	reservations.reservations["paid"].ExpiresAt = past
+	if err := inv.Commit("paid"); err != nil {
+		t.Fatal(err)
+	}
+
+	released, err := inv.ReleaseExpired()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if released != 1 {
+		t.Errorf("released = %d, want 1", released)
+	}
+	assertStock(t, products, "a", 6, 2)
+
+	if released, err := inv.ReleaseExpired(); err != nil || released != 0 {
+		t.Errorf("second run: released = %d, err = %v; want 0, nil", released, err)
+	}
+	assertStock(t, products, "a", 6, 2)
+}
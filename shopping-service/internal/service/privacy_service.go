@@ -0,0 +1,45 @@
+package service
+
+import (
+	"shopping-service/internal/domain"
+	"shopping-service/internal/ports"
+)
+
+// PrivacyService answers data export and erasure requests from auth-service.
+type PrivacyService struct {
+	products     ports.ProductRepository
+	carts        CartRepo
+	reservations ports.ReservationRepository
+}
+
+func NewPrivacyService(products ports.ProductRepository, carts CartRepo, reservations ports.ReservationRepository) *PrivacyService {
+	return &PrivacyService{products: products, carts: carts, reservations: reservations}
+}
+
+func (s *PrivacyService) ExportUserData(userID string) (*domain.UserData, error) {
+	cart, err := s.carts.GetCart(userID)
+	if err != nil {
+		return nil, err
+	}
+	products, err := s.products.FindByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	reservations, err := s.reservations.FindByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.UserData{Cart: cart, Products: products, Reservations: reservations}, nil
+}
+
+// EraseUserData deletes the cart. Products and stock reservations are kept
+// but no longer reference the user.
+func (s *PrivacyService) EraseUserData(userID string) error {
+	if err := s.carts.ClearCart(userID); err != nil {
+		return err
+	}
+	if err := s.products.AnonymizeUser(userID); err != nil {
+		return err
+	}
+	return s.reservations.AnonymizeUser(userID)
+}
@@ -1,36 +1,213 @@
 package service
 
 import (
+	"errors"
+	"fmt"
 	"shopping-service/internal/domain"
 	"shopping-service/internal/ports"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	ErrProductNotFound = errors.New("product not found")
+	ErrNotProductOwner = errors.New("product belongs to another user")
+	ErrInvalidProduct  = errors.New("name must not be empty, price and stock must not be negative")
+	ErrUnknownCategory = errors.New("unknown category")
+	ErrProductReserved = errors.New("product has pending reservations")
+
+	ErrInvalidProductFilter = errors.New("invalid product filter")
+	ErrInvalidSearchQuery   = errors.New("search query must be between 1 and 200 characters")
+)
+
+const (
+	defaultProductPageSize = 20
+	maxProductPageSize     = 100
+	defaultSearchLimit     = 20
+	maxSearchLimit         = 50
+	maxSearchQueryLength   = 200
 )
 
 type ProductService struct {
-	repo ports.ProductRepository
+	repo         ports.ProductRepository
+	search       ports.ProductSearch
+	categories   ports.CategoryRepository
+	reservations ports.ReservationRepository
 }
 
 // Removed incomplete NewCartService method causing missing return error.
 
 // NewProductService creates a new ProductService instance.
-func NewProductService(r ports.ProductRepository) *ProductService {
-	return &ProductService{repo: r}
+func NewProductService(r ports.ProductRepository, search ports.ProductSearch, categories ports.CategoryRepository, reservations ports.ReservationRepository) *ProductService {
+	return &ProductService{repo: r, search: search, categories: categories, reservations: reservations}
 }
 
 func (s *ProductService) CreateProduct(p *domain.Product) error {
+	if p.Stock != nil && *p.Stock < 0 {
+		return ErrInvalidProduct
+	}
+	p.Reserved = 0
+	if err := s.checkCategories(p.CategoryIDs); err != nil {
+		return err
+	}
 	return s.repo.Create(p)
 }
 
-func (s *ProductService) ListProducts() ([]domain.Product, error) {
-	return s.repo.FindAll()
+// ListProducts returns one page of the catalog, by default the oldest
+// products first.
+func (s *ProductService) ListProducts(filter domain.ProductFilter) (*domain.ProductPage, error) {
+	switch strings.TrimPrefix(filter.Sort, "-") {
+	case "":
+		filter.Sort = domain.ProductSortCreated
+	case domain.ProductSortCreated, domain.ProductSortName, domain.ProductSortPrice:
+	default:
+		return nil, fmt.Errorf("%w: unknown sort %q", ErrInvalidProductFilter, filter.Sort)
+	}
+	if filter.MinPrice != nil && filter.MaxPrice != nil && *filter.MinPrice > *filter.MaxPrice {
+		return nil, fmt.Errorf("%w: min_price is greater than max_price", ErrInvalidProductFilter)
+	}
+	if filter.Limit < 1 {
+		filter.Limit = defaultProductPageSize
+	}
+	if filter.Limit > maxProductPageSize {
+		filter.Limit = maxProductPageSize
+	}
+
+	page, err := s.repo.List(filter)
+	if errors.Is(err, ports.ErrInvalidCursor) {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidProductFilter, err)
+	}
+	return page, err
+}
+
+func (s *ProductService) GetProductByID(id string) (*domain.Product, error) {
+	return s.repo.FindByID(id)
+}
+
+// SearchProducts returns the products matching query, the most relevant first.
+func (s *ProductService) SearchProducts(query string, limit int) ([]domain.ProductSearchHit, error) {
+	query = strings.TrimSpace(query)
+	if query == "" || utf8.RuneCountInString(query) > maxSearchQueryLength {
+		return nil, ErrInvalidSearchQuery
+	}
+	if limit < 1 {
+		limit = defaultSearchLimit
+	}
+	if limit > maxSearchLimit {
+		limit = maxSearchLimit
+	}
+	return s.search.Search(query, limit)
 }
-func (s *ProductService) GetAllProducts() ([]domain.Product, error) {
-	products, err := s.repo.FindAll()
+
+// UpdateProduct changes the given fields. Archived products can't be edited.
+// Stock is the quantity available for sale; units reserved by pending orders
+// are not included.
+func (s *ProductService) UpdateProduct(id string, update domain.ProductUpdate, userID string, anyOwner bool) (*domain.Product, error) {
+	product, err := s.ownedProduct(id, userID, anyOwner)
 	if err != nil {
 		return nil, err
 	}
-	return products, nil
+	if product.Archived {
+		return nil, ErrProductNotFound
+	}
+	if update.Name != nil {
+		product.Name = strings.TrimSpace(*update.Name)
+	}
+	if update.Description != nil {
+		product.Description = strings.TrimSpace(*update.Description)
+	}
+	if update.Price != nil {
+		product.Price = *update.Price
+	}
+	if update.CategoryIDs != nil {
+		if err := s.checkCategories(*update.CategoryIDs); err != nil {
+			return nil, err
+		}
+		product.CategoryIDs = *update.CategoryIDs
+	}
+	if update.Stock != nil {
+		product.Stock = update.Stock
+	}
+	if product.Name == "" || product.Price < 0 || (product.Stock != nil && *product.Stock < 0) {
+		return nil, ErrInvalidProduct
+	}
+	now := time.Now()
+	product.UpdatedAt = &now
+	if err := s.repo.Update(product, update.Stock != nil); err != nil {
+		return nil, err
+	}
+	return product, nil
 }
 
-func (s *ProductService) GetProductByID(id string) (*domain.Product, error) {
-	return s.repo.FindByID(id)
+// ArchiveProduct removes a product from the catalog. Archiving an archived
+// product again changes nothing.
+func (s *ProductService) ArchiveProduct(id, userID string, anyOwner bool) (*domain.Product, error) {
+	product, err := s.ownedProduct(id, userID, anyOwner)
+	if err != nil {
+		return nil, err
+	}
+	if product.Archived {
+		return product, nil
+	}
+	now := time.Now()
+	product.Archived = true
+	product.ArchivedAt = &now
+	product.UpdatedAt = &now
+	if err := s.repo.Update(product, false); err != nil {
+		return nil, err
+	}
+	return product, nil
+}
+
+// DeleteProduct removes a product permanently and returns it as it was.
+// Products held by pending reservations can't be deleted, as committing or
+// releasing those would fail; archive them instead.
+func (s *ProductService) DeleteProduct(id, userID string, anyOwner bool) (*domain.Product, error) {
+	product, err := s.ownedProduct(id, userID, anyOwner)
+	if err != nil {
+		return nil, err
+	}
+	reserved, err := s.reservations.HasPending(id)
+	if err != nil {
+		return nil, err
+	}
+	if reserved {
+		return nil, ErrProductReserved
+	}
+	if err := s.repo.Delete(id); err != nil {
+		return nil, err
+	}
+	return product, nil
+}
+
+func (s *ProductService) ownedProduct(id, userID string, anyOwner bool) (*domain.Product, error) {
+	product, err := s.repo.FindByID(id)
+	if err != nil {
+		return nil, ErrProductNotFound
+	}
+	if !anyOwner && product.UserID != userID {
+		return nil, ErrNotProductOwner
+	}
+	return product, nil
+}
+
+// checkCategories makes sure a product is only assigned to existing categories.
+func (s *ProductService) checkCategories(ids []primitive.ObjectID) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	found, err := s.categories.FindByIDs(ids)
+	if err != nil {
+		return err
+	}
+	for _, id := range ids {
+		if !slices.ContainsFunc(found, func(c domain.Category) bool { return c.ID == id }) {
+			return fmt.Errorf("%w: %s", ErrUnknownCategory, id.Hex())
+		}
+	}
+	return nil
 }